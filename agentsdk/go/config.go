@@ -2,6 +2,9 @@ package agentsdk
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"time"
 )
 
 type Config struct {
@@ -14,6 +17,20 @@ type Config struct {
 
 	ServiceName    string
 	ServiceVersion string
+
+	// WALDir is the base directory Session's write-ahead log is stored
+	// under, one subdirectory per session ID. It must be stable across
+	// restarts for RecoverSessions to find sessions a prior process didn't
+	// finish delivering.
+	WALDir string
+
+	// CloseDeadline bounds how long Session.Close waits for the WAL to
+	// drain to every configured sink before returning.
+	CloseDeadline time.Duration
+
+	// PricingTable estimates gen_ai.usage.cost from an LLMCall's token
+	// usage. Defaults to DefaultPricingTable.
+	PricingTable PricingTable
 }
 
 func (c *Config) Validate() error {
@@ -26,6 +43,15 @@ func (c *Config) Validate() error {
 	if c.ServiceName == "" {
 		c.ServiceName = "agent"
 	}
+	if c.WALDir == "" {
+		c.WALDir = filepath.Join(os.TempDir(), "agentsdk-wal")
+	}
+	if c.CloseDeadline <= 0 {
+		c.CloseDeadline = 10 * time.Second
+	}
+	if c.PricingTable == nil {
+		c.PricingTable = DefaultPricingTable
+	}
 	return nil
 }
 
@@ -37,6 +63,9 @@ func DefaultConfig() *Config {
 		OTLPInsecure:   true,
 		ServiceName:    "agent",
 		ServiceVersion: "1.0.0",
+		WALDir:         filepath.Join(os.TempDir(), "agentsdk-wal"),
+		CloseDeadline:  10 * time.Second,
+		PricingTable:   DefaultPricingTable,
 	}
 }
 
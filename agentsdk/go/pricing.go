@@ -0,0 +1,37 @@
+package agentsdk
+
+// ModelPricing is the per-million-token cost of a model, used to estimate
+// gen_ai.usage.cost from the token counts an LLMCall reports.
+type ModelPricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// PricingTable maps "provider/model" to its ModelPricing. Session looks up
+// Config.PricingTable (falling back to DefaultPricingTable) when an LLMCall
+// ends, to attach an estimated gen_ai.usage.cost attribute.
+type PricingTable map[string]ModelPricing
+
+// DefaultPricingTable has rough list prices, in USD, for a handful of
+// widely used models. It's a reasonable default, not a source of truth;
+// set Config.PricingTable to override or extend it.
+var DefaultPricingTable = PricingTable{
+	"openai/gpt-4":              {InputPerMillion: 30, OutputPerMillion: 60},
+	"openai/gpt-4o":             {InputPerMillion: 5, OutputPerMillion: 15},
+	"openai/gpt-4o-mini":        {InputPerMillion: 0.15, OutputPerMillion: 0.6},
+	"anthropic/claude-3-opus":   {InputPerMillion: 15, OutputPerMillion: 75},
+	"anthropic/claude-3-sonnet": {InputPerMillion: 3, OutputPerMillion: 15},
+	"anthropic/claude-3-haiku":  {InputPerMillion: 0.25, OutputPerMillion: 1.25},
+}
+
+// Cost estimates the USD cost of a call against provider/model given its
+// token usage. ok is false if the table has no entry for provider/model.
+func (t PricingTable) Cost(provider, model string, inputTokens, outputTokens int64) (cost float64, ok bool) {
+	pricing, ok := t[provider+"/"+model]
+	if !ok {
+		return 0, false
+	}
+	cost = float64(inputTokens)/1_000_000*pricing.InputPerMillion +
+		float64(outputTokens)/1_000_000*pricing.OutputPerMillion
+	return cost, true
+}
@@ -0,0 +1,240 @@
+package agentsdk
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	producerBaseBackoff = 200 * time.Millisecond
+	producerMaxBackoff  = 30 * time.Second
+
+	// circuitBreakerThreshold is the number of consecutive delivery
+	// failures a sink tolerates before the breaker opens and the sink is
+	// given a cooldown period before being retried again.
+	circuitBreakerThreshold = 5
+	circuitBreakerCooldown  = 15 * time.Second
+)
+
+// producer is the durable event pipeline behind a Session: every emitted
+// event is written to a WAL synchronously, and one retryingSink per
+// registered Sink tails that WAL independently, retrying with backoff and a
+// circuit breaker until the sink acks delivery.
+type producer struct {
+	wal     *WAL
+	sinks   []*retryingSink
+	metrics *producerMetrics
+}
+
+func newProducer(wal *WAL, sinks []namedSink) (*producer, error) {
+	metrics, err := newProducerMetrics(wal)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &producer{wal: wal, metrics: metrics}
+	for _, ns := range sinks {
+		cursor, err := wal.OpenCursor(ns.name)
+		if err != nil {
+			return nil, err
+		}
+		p.sinks = append(p.sinks, &retryingSink{
+			name:     ns.name,
+			sink:     ns.sink,
+			cursor:   cursor,
+			metrics:  metrics,
+			done:     make(chan struct{}),
+			stopping: make(chan struct{}),
+		})
+	}
+	return p, nil
+}
+
+// namedSink pairs a Sink with a stable name, used both as its WAL cursor
+// name and for diagnostics; the name must stay stable across restarts so
+// recovery resumes the right cursor.
+type namedSink struct {
+	name string
+	sink Sink
+}
+
+func (p *producer) start(streamID string) error {
+	for _, rs := range p.sinks {
+		if err := rs.sink.EnsureStream(context.Background(), streamID); err != nil {
+			return err
+		}
+		go rs.run(streamID)
+	}
+	return nil
+}
+
+// allDrained reports whether every sink has delivered and acked all the way
+// to the end of the WAL.
+func (p *producer) allDrained() bool {
+	for _, rs := range p.sinks {
+		seg, offset := rs.cursor.position()
+		if !p.wal.isAtTail(seg, offset) {
+			return false
+		}
+	}
+	return true
+}
+
+// enqueue durably appends event to the WAL; every retryingSink will observe
+// it on its next tail iteration.
+func (p *producer) enqueue(event *Event) error {
+	if _, err := p.wal.Append(event); err != nil {
+		p.metrics.recordDropped(context.Background(), 1)
+		return err
+	}
+	p.metrics.recordEnqueued(context.Background(), 1)
+	return nil
+}
+
+// close signals every sink's goroutine to stop, waits up to deadline for
+// them to exit, then closes each sink and the WAL. It does not wait for
+// sinks to catch up: Session.Close and recoverSession wait for that with
+// waitDrained before calling close. Records a sink has not acked stay in the
+// WAL with its cursor position on disk, so a future RecoverSessions call can
+// finish delivering them.
+func (p *producer) close(ctx context.Context, deadline time.Duration) error {
+	deadlineCtx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	for _, rs := range p.sinks {
+		close(rs.stopping)
+	}
+	for _, rs := range p.sinks {
+		select {
+		case <-rs.done:
+		case <-deadlineCtx.Done():
+		}
+	}
+
+	for _, rs := range p.sinks {
+		_ = rs.sink.Close(ctx)
+	}
+	return p.wal.Close()
+}
+
+// retryingSink tails the WAL for one sink, retrying each record with
+// exponential backoff and jitter, and tripping a circuit breaker after
+// repeated consecutive failures so a down sink doesn't spin hot.
+type retryingSink struct {
+	name     string
+	sink     Sink
+	cursor   *walCursor
+	metrics  *producerMetrics
+	done     chan struct{}
+	stopping chan struct{}
+}
+
+func (rs *retryingSink) run(streamID string) {
+	defer close(rs.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-rs.stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	consecutiveFailures := 0
+
+	for {
+		rec, err := rs.cursor.Next(ctx)
+		if err != nil {
+			return
+		}
+
+		for attempt := 0; ; attempt++ {
+			if consecutiveFailures >= circuitBreakerThreshold {
+				select {
+				case <-time.After(circuitBreakerCooldown):
+				case <-rs.stopping:
+					return
+				}
+			}
+
+			appendErr := rs.sink.Append(ctx, streamID, []*Event{rec.Event})
+			if appendErr == nil {
+				consecutiveFailures = 0
+				break
+			}
+
+			consecutiveFailures++
+			select {
+			case <-time.After(backoffWithJitter(attempt)):
+			case <-rs.stopping:
+				return
+			}
+		}
+
+		if err := rs.cursor.Ack(); err != nil {
+			return
+		}
+		rs.metrics.recordAcked(ctx, 1)
+	}
+}
+
+func backoffWithJitter(attempt int) time.Duration {
+	d := producerBaseBackoff << attempt
+	if d <= 0 || d > producerMaxBackoff {
+		d = producerMaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// producerMetrics exposes the WAL-backed pipeline's health via OTEL meters.
+type producerMetrics struct {
+	enqueued metric.Int64Counter
+	acked    metric.Int64Counter
+	dropped  metric.Int64Counter
+}
+
+func newProducerMetrics(wal *WAL) (*producerMetrics, error) {
+	meter := otel.Meter("agentsdk")
+
+	enqueued, err := meter.Int64Counter("agentsdk.producer.events_enqueued")
+	if err != nil {
+		return nil, err
+	}
+	acked, err := meter.Int64Counter("agentsdk.producer.events_acked")
+	if err != nil {
+		return nil, err
+	}
+	dropped, err := meter.Int64Counter("agentsdk.producer.events_dropped")
+	if err != nil {
+		return nil, err
+	}
+	_, err = meter.Int64ObservableGauge("agentsdk.producer.wal_bytes",
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(wal.SizeBytes())
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &producerMetrics{enqueued: enqueued, acked: acked, dropped: dropped}, nil
+}
+
+func (m *producerMetrics) recordEnqueued(ctx context.Context, n int64) {
+	m.enqueued.Add(ctx, n)
+}
+
+func (m *producerMetrics) recordAcked(ctx context.Context, n int64) {
+	m.acked.Add(ctx, n)
+}
+
+func (m *producerMetrics) recordDropped(ctx context.Context, n int64) {
+	m.dropped.Add(ctx, n)
+}
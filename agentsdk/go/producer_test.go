@@ -0,0 +1,165 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoffWithJitterStaysWithinBounds(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := producerBaseBackoff << attempt
+		if d <= 0 || d > producerMaxBackoff {
+			d = producerMaxBackoff
+		}
+
+		for i := 0; i < 20; i++ {
+			got := backoffWithJitter(attempt)
+			if got < d/2 || got > d {
+				t.Fatalf("attempt %d: backoffWithJitter() = %v, want in [%v, %v]", attempt, got, d/2, d)
+			}
+		}
+	}
+}
+
+func TestBackoffWithJitterCapsAtMaxBackoff(t *testing.T) {
+	got := backoffWithJitter(30)
+	if got > producerMaxBackoff {
+		t.Errorf("backoffWithJitter(30) = %v, want <= %v", got, producerMaxBackoff)
+	}
+}
+
+// countingFailThenSucceedSink fails the first failures calls to Append and
+// succeeds after that, recording every attempt on calls for the test to
+// synchronize on.
+type countingFailThenSucceedSink struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (s *countingFailThenSucceedSink) EnsureStream(ctx context.Context, streamID string) error {
+	return nil
+}
+
+func (s *countingFailThenSucceedSink) Append(ctx context.Context, streamID string, events []*Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("simulated sink failure")
+	}
+	return nil
+}
+
+func (s *countingFailThenSucceedSink) Close(ctx context.Context) error { return nil }
+
+func TestRetryingSinkRetriesUntilSinkSucceeds(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenWAL(dir, defaultWALSegmentBytes)
+	if err != nil {
+		t.Fatalf("OpenWAL() error = %v", err)
+	}
+	defer wal.Close()
+
+	sink := &countingFailThenSucceedSink{failures: 2}
+	p, err := newProducer(wal, []namedSink{{name: "test-sink", sink: sink}})
+	if err != nil {
+		t.Fatalf("newProducer() error = %v", err)
+	}
+	if err := p.start("stream-1"); err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	defer p.close(context.Background(), time.Second)
+
+	if err := p.enqueue(&Event{Type: EventCustom, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("enqueue() error = %v", err)
+	}
+
+	deadline := time.After(3 * time.Second)
+	for {
+		sink.mu.Lock()
+		calls := sink.calls
+		sink.mu.Unlock()
+		if calls > sink.failures {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("sink only saw %d calls, want more than %d failures before a success", calls, sink.failures)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.calls != sink.failures+1 {
+		t.Errorf("sink received %d calls, want %d (failures + the succeeding retry)", sink.calls, sink.failures+1)
+	}
+}
+
+// alwaysFailSink fails every Append and reports each attempt on calls, so a
+// test can wait for the circuit breaker to trip without guessing timing.
+type alwaysFailSink struct {
+	calls chan struct{}
+}
+
+func (s *alwaysFailSink) EnsureStream(ctx context.Context, streamID string) error { return nil }
+
+func (s *alwaysFailSink) Append(ctx context.Context, streamID string, events []*Event) error {
+	s.calls <- struct{}{}
+	return errors.New("simulated sink failure")
+}
+
+func (s *alwaysFailSink) Close(ctx context.Context) error { return nil }
+
+// TestRetryingSinkCircuitBreakerCooldownStopsOnClose confirms that once the
+// circuit breaker has tripped (circuitBreakerThreshold consecutive
+// failures) and run() is waiting out circuitBreakerCooldown, closing
+// rs.stopping interrupts that wait immediately instead of blocking for the
+// full cooldown - otherwise Session.Close would stall for up to
+// circuitBreakerCooldown on every open-breaker sink.
+func TestRetryingSinkCircuitBreakerCooldownStopsOnClose(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenWAL(dir, defaultWALSegmentBytes)
+	if err != nil {
+		t.Fatalf("OpenWAL() error = %v", err)
+	}
+	defer wal.Close()
+
+	sink := &alwaysFailSink{calls: make(chan struct{}, circuitBreakerThreshold+1)}
+	p, err := newProducer(wal, []namedSink{{name: "test-sink", sink: sink}})
+	if err != nil {
+		t.Fatalf("newProducer() error = %v", err)
+	}
+	if err := p.start("stream-1"); err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+
+	if err := p.enqueue(&Event{Type: EventCustom, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("enqueue() error = %v", err)
+	}
+
+	for i := 0; i < circuitBreakerThreshold; i++ {
+		select {
+		case <-sink.calls:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("sink did not see %d attempts within deadline", circuitBreakerThreshold)
+		}
+	}
+
+	// The last failure is still followed by its own backoff (attempt
+	// circuitBreakerThreshold-1, at most producerBaseBackoff shifted by that
+	// much); wait it out so run() has moved on to the cooldown wait.
+	time.Sleep(producerBaseBackoff<<(circuitBreakerThreshold-1) + 200*time.Millisecond)
+
+	start := time.Now()
+	if err := p.close(context.Background(), time.Second); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("close() took %v, want well under circuitBreakerCooldown (%v)", elapsed, circuitBreakerCooldown)
+	}
+}
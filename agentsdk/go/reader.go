@@ -0,0 +1,104 @@
+package agentsdk
+
+import (
+	"context"
+	"time"
+)
+
+// defaultTailPollInterval is how often Reader polls S2 for new records while
+// following a stream live.
+const defaultTailPollInterval = 500 * time.Millisecond
+
+// TailOptions configures what a Reader replays and whether it keeps
+// following a stream after catching up.
+type TailOptions struct {
+	// FromSequence replays records after this sequence number. Zero replays
+	// the stream from the beginning.
+	FromSequence int64
+	// FromTime, if set, drops replayed records with a Timestamp before it.
+	// Filtering happens client-side, after FromSequence has already limited
+	// what's fetched from S2.
+	FromTime time.Time
+	// Follow keeps the Reader polling for new records after it reaches the
+	// end of the stream, instead of closing the returned channel.
+	Follow bool
+	// PollInterval overrides how often the Reader polls S2 while following.
+	// Defaults to defaultTailPollInterval.
+	PollInterval time.Duration
+}
+
+// TailEvent pairs an Event with any error hit while fetching it. Err is set
+// (with Event nil) only once, on the final value sent before the channel is
+// closed.
+type TailEvent struct {
+	Event *Event
+	Err   error
+}
+
+// Reader replays and tails the events appended to a single S2 stream,
+// backing both historical replay (from a sequence number or timestamp) and
+// live follow mode off the same polling loop.
+type Reader struct {
+	client     *S2Client
+	streamName string
+}
+
+// NewReader returns a Reader over streamName, polling S2 through client.
+func NewReader(client *S2Client, streamName string) *Reader {
+	return &Reader{client: client, streamName: streamName}
+}
+
+// Tail streams events on the returned channel. With opts.Follow false, the
+// channel closes once the stream has been replayed up to its current end;
+// with it true, Tail keeps polling for new records until ctx is done. The
+// channel is always closed before Tail's goroutine exits, and a fetch error
+// is delivered as a final TailEvent with Err set.
+func (r *Reader) Tail(ctx context.Context, opts TailOptions) <-chan TailEvent {
+	out := make(chan TailEvent)
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultTailPollInterval
+	}
+
+	sr := r.client.NewStreamReader(r.streamName)
+	sr.lastSeq = opts.FromSequence
+
+	go func() {
+		defer close(out)
+
+		for {
+			events, err := sr.ReadEvents(ctx)
+			if err != nil {
+				select {
+				case out <- TailEvent{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			for _, event := range events {
+				if !opts.FromTime.IsZero() && event.Timestamp.Before(opts.FromTime) {
+					continue
+				}
+				select {
+				case out <- TailEvent{Event: event}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if !opts.Follow && len(events) == 0 {
+				return
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,198 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS2Records is a minimal stand-in for S2's record store: it serves
+// GET /streams/{name}/records?after=N the same way StreamReader.ReadEvents
+// expects, from an in-memory, mutable sequence of records so tests can
+// append new records mid-test to exercise Reader.Tail's follow mode.
+type fakeS2Records struct {
+	mu      sync.Mutex
+	records []fakeRecord
+}
+
+type fakeRecord struct {
+	Sequence int64  `json:"sequence"`
+	Body     string `json:"body"`
+}
+
+func newFakeS2Records() *fakeS2Records {
+	return &fakeS2Records{}
+}
+
+func (f *fakeS2Records) append(event *Event, sequence int64) {
+	body, err := event.ToJSON()
+	if err != nil {
+		panic(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records = append(f.records, fakeRecord{Sequence: sequence, Body: string(body)})
+}
+
+func (f *fakeS2Records) server() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		after, _ := strconv.ParseInt(req.URL.Query().Get("after"), 10, 64)
+
+		f.mu.Lock()
+		var matched []fakeRecord
+		for _, rec := range f.records {
+			if rec.Sequence > after {
+				matched = append(matched, rec)
+			}
+		}
+		f.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Records []fakeRecord `json:"records"`
+		}{Records: matched})
+	}))
+}
+
+func newTestReader(t *testing.T, f *fakeS2Records) *Reader {
+	t.Helper()
+	srv := f.server()
+	t.Cleanup(srv.Close)
+	client := NewS2Client(srv.URL, "test-key")
+	return NewReader(client, "test-stream")
+}
+
+func drainTail(t *testing.T, ch <-chan TailEvent, timeout time.Duration) []TailEvent {
+	t.Helper()
+	var got []TailEvent
+	deadline := time.After(timeout)
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, ev)
+		case <-deadline:
+			t.Fatalf("Tail() did not close its channel within %v; got %d events so far", timeout, len(got))
+		}
+	}
+}
+
+func TestReaderTailReplaysExistingRecords(t *testing.T) {
+	f := newFakeS2Records()
+	base := time.Now()
+	for i := int64(1); i <= 3; i++ {
+		f.append(&Event{Type: EventCustom, Timestamp: base.Add(time.Duration(i) * time.Second)}, i)
+	}
+
+	reader := newTestReader(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	got := drainTail(t, reader.Tail(ctx, TailOptions{}), time.Second)
+	if len(got) != 3 {
+		t.Fatalf("got %d events, want 3", len(got))
+	}
+	for _, ev := range got {
+		if ev.Err != nil {
+			t.Errorf("unexpected error in TailEvent: %v", ev.Err)
+		}
+	}
+}
+
+func TestReaderTailFromSequenceSkipsOlderRecords(t *testing.T) {
+	f := newFakeS2Records()
+	base := time.Now()
+	for i := int64(1); i <= 5; i++ {
+		f.append(&Event{Type: EventCustom, Timestamp: base.Add(time.Duration(i) * time.Second), Sequence: i}, i)
+	}
+
+	reader := newTestReader(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	got := drainTail(t, reader.Tail(ctx, TailOptions{FromSequence: 3}), time.Second)
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2 (sequences 4 and 5)", len(got))
+	}
+	for _, ev := range got {
+		if ev.Event.Sequence <= 3 {
+			t.Errorf("event with Sequence %d should have been filtered out by FromSequence=3", ev.Event.Sequence)
+		}
+	}
+}
+
+func TestReaderTailFromTimeDropsEarlierRecords(t *testing.T) {
+	f := newFakeS2Records()
+	base := time.Now()
+	f.append(&Event{Type: EventCustom, Timestamp: base}, 1)
+	cutoff := base.Add(time.Hour)
+	f.append(&Event{Type: EventCustom, Timestamp: cutoff.Add(time.Minute)}, 2)
+
+	reader := newTestReader(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	got := drainTail(t, reader.Tail(ctx, TailOptions{FromTime: cutoff}), time.Second)
+	if len(got) != 1 {
+		t.Fatalf("got %d events, want 1 (only the record after FromTime)", len(got))
+	}
+	if !got[0].Event.Timestamp.After(cutoff) {
+		t.Errorf("surviving event Timestamp = %v, want after %v", got[0].Event.Timestamp, cutoff)
+	}
+}
+
+func TestReaderTailFollowDeliversRecordsAppendedLater(t *testing.T) {
+	f := newFakeS2Records()
+	f.append(&Event{Type: EventCustom, Timestamp: time.Now()}, 1)
+
+	reader := newTestReader(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	out := reader.Tail(ctx, TailOptions{Follow: true, PollInterval: 20 * time.Millisecond})
+
+	first := <-out
+	if first.Err != nil || first.Event == nil {
+		t.Fatalf("first TailEvent = %+v, want the pre-existing record", first)
+	}
+
+	f.append(&Event{Type: EventCustom, Timestamp: time.Now()}, 2)
+
+	select {
+	case second := <-out:
+		if second.Err != nil || second.Event == nil {
+			t.Fatalf("second TailEvent = %+v, want the record appended while following", second)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Follow mode did not deliver the record appended after the initial replay")
+	}
+
+	cancel()
+	for range out {
+	}
+}
+
+func TestReaderTailSurfacesFetchErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := NewS2Client(srv.URL, "test-key")
+	reader := NewReader(client, "test-stream")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	got := drainTail(t, reader.Tail(ctx, TailOptions{}), time.Second)
+	if len(got) != 1 || got[0].Err == nil {
+		t.Fatalf("got %+v, want a single TailEvent carrying the fetch error", got)
+	}
+}
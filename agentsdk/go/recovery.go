@@ -0,0 +1,120 @@
+package agentsdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// recoveryShutdownGrace is the time given to retryingSink goroutines to
+// notice a stop signal and exit once waitDrained has already decided a
+// session is done (or out of time).
+const recoveryShutdownGrace = 5 * time.Second
+
+// RecoverSessions scans walDir for session subdirectories left behind by a
+// process that exited before Session.Close drained them, and resends any
+// un-acked records to sinks. It should be called once at startup, before
+// any new sessions are created under the same walDir.
+//
+// sinks must be built the same way NewSession builds them for a live
+// session: the extra sinks passed to WithSink, in the same order, followed
+// by the S2Client sink implied by cfg.S2Endpoint/cfg.S2APIKey if those are
+// set. Otherwise the names recovery assigns won't match the WAL cursors a
+// prior process left on disk and un-acked records won't resume correctly.
+//
+// Each session is given perSessionDeadline for all of its sinks together to
+// drain its backlog; sessions that fully drain within the deadline have their
+// WAL directory removed,
+// matching normal Session.Close behavior. Sessions that don't finish in time
+// are left on disk for the next call to RecoverSessions to retry.
+func RecoverSessions(ctx context.Context, cfg *Config, sinks []Sink, perSessionDeadline time.Duration) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	entries, err := os.ReadDir(cfg.WALDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list WAL dir: %w", err)
+	}
+
+	named := make([]namedSink, len(sinks))
+	for i, sink := range sinks {
+		named[i] = namedSink{name: fmt.Sprintf("sink-%d", i), sink: sink}
+	}
+	if cfg.S2Endpoint != "" && cfg.S2APIKey != "" {
+		named = append(named, namedSink{name: "s2", sink: NewS2Client(cfg.S2Endpoint, cfg.S2APIKey)})
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		sessionDir := filepath.Join(cfg.WALDir, entry.Name())
+		streamID := cfg.S2StreamPrefix + entry.Name()
+		if err := recoverSession(ctx, sessionDir, streamID, named, perSessionDeadline); err != nil {
+			return fmt.Errorf("failed to recover session %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+func recoverSession(ctx context.Context, sessionDir, streamID string, named []namedSink, deadline time.Duration) error {
+	wal, err := OpenWAL(sessionDir, defaultWALSegmentBytes)
+	if err != nil {
+		return err
+	}
+
+	p, err := newProducer(wal, named)
+	if err != nil {
+		return err
+	}
+
+	if err := p.start(streamID); err != nil {
+		_ = wal.Close()
+		return err
+	}
+
+	drained := false
+	select {
+	case <-waitDrained(p, deadline):
+		drained = p.allDrained()
+	case <-ctx.Done():
+	}
+
+	if err := p.close(ctx, recoveryShutdownGrace); err != nil {
+		return err
+	}
+
+	if drained {
+		return os.RemoveAll(sessionDir)
+	}
+	return nil
+}
+
+// waitDrained polls until every sink has caught up to the end of the WAL or
+// deadline elapses, whichever comes first.
+func waitDrained(p *producer, deadline time.Duration) <-chan struct{} {
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		timeout := time.After(deadline)
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			if p.allDrained() {
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-timeout:
+				return
+			}
+		}
+	}()
+	return doneCh
+}
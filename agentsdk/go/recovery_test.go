@@ -0,0 +1,74 @@
+package agentsdk
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecoverSessionsDeliversPendingEventsAndCleansUp(t *testing.T) {
+	walDir := t.TempDir()
+	sessionID := "session-under-recovery"
+	sessionDir := filepath.Join(walDir, sessionID)
+
+	wal, err := OpenWAL(sessionDir, defaultWALSegmentBytes)
+	if err != nil {
+		t.Fatalf("OpenWAL() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := wal.Append(&Event{Type: EventCustom, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "recovered.ndjson")
+	sink, err := NewFileSink(outPath)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+
+	cfg := &Config{S2Endpoint: "https://api.s2.dev", S2StreamPrefix: "agent-session-", WALDir: walDir}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if err := RecoverSessions(context.Background(), cfg, []Sink{sink}, time.Second); err != nil {
+		t.Fatalf("RecoverSessions() error = %v", err)
+	}
+
+	f, err := os.Open(outPath)
+	if err != nil {
+		t.Fatalf("Open() recovered output error = %v", err)
+	}
+	defer f.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("recovered %d events, want 3", lines)
+	}
+
+	if _, err := os.Stat(sessionDir); !os.IsNotExist(err) {
+		t.Errorf("expected session WAL dir %s to be removed after a full drain, stat err = %v", sessionDir, err)
+	}
+}
+
+func TestRecoverSessionsNoWALDirIsNoop(t *testing.T) {
+	cfg := &Config{S2Endpoint: "https://api.s2.dev", S2StreamPrefix: "agent-session-", WALDir: filepath.Join(t.TempDir(), "does-not-exist")}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if err := RecoverSessions(context.Background(), cfg, nil, time.Second); err != nil {
+		t.Errorf("RecoverSessions() error = %v, want nil for a missing WAL dir", err)
+	}
+}
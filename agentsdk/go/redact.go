@@ -0,0 +1,81 @@
+package agentsdk
+
+import "github.com/agent-observability/internal/redact"
+
+// The types below are aliases onto the shared redaction pipeline in
+// internal/redact, which also backs s2exporter's RedactionPipeline. Keeping
+// them here (rather than asking callers to import internal/redact directly,
+// which they couldn't anyway) preserves the agentsdk.RedactionPipeline /
+// agentsdk.WithRedactor API this package has always exposed.
+
+// RedactionKind identifies what category of sensitive content a Redactor
+// found, and is recorded in the gen_ai.redaction.kinds span attribute.
+type RedactionKind = redact.RedactionKind
+
+const (
+	RedactionEmail      = redact.RedactionEmail
+	RedactionCreditCard = redact.RedactionCreditCard
+	RedactionAPIKey     = redact.RedactionAPIKey
+	RedactionSecret     = redact.RedactionSecret
+	RedactionDLP        = redact.RedactionDLP
+)
+
+// Match is one span of sensitive content a Redactor found within a string,
+// identified by byte offset so a Policy can replace or hash just that span.
+type Match = redact.Match
+
+// Redactor finds sensitive content within a string value. Built-in
+// implementations are regex- or entropy-based; DLPRedactor adapts an
+// external scanning service into the same interface.
+type Redactor = redact.Redactor
+
+// Policy decides what to do with the matches a Redactor found: replace them
+// in place (e.g. with a "[REDACTED:<kind>]" marker or a hash) or drop the
+// event carrying them entirely.
+type Policy = redact.Policy
+
+// RedactionPipeline runs a set of Redactors over event data and lets a
+// single Policy decide what happens to whatever they find. Install one on a
+// Session via WithRedactor.
+type RedactionPipeline = redact.RedactionPipeline
+
+// NewRedactionPipeline builds a pipeline that runs redactors (in order,
+// accumulating all of their matches) and hands the result to policy.
+var NewRedactionPipeline = redact.NewRedactionPipeline
+
+// MarkerPolicy replaces each match with "[REDACTED:<kind>]".
+type MarkerPolicy = redact.MarkerPolicy
+
+// HashPolicy replaces each match with a "sha256:<hex>" digest of the
+// matched text, preserving the ability to correlate repeated occurrences
+// of the same secret without retaining it.
+type HashPolicy = redact.HashPolicy
+
+// DropPolicy discards the whole event whenever any redactor finds a match,
+// rather than trying to sanitize it in place.
+type DropPolicy = redact.DropPolicy
+
+// RegexRedactor reports every match of Pattern as Kind.
+type RegexRedactor = redact.RegexRedactor
+
+// NewEmailRedactor flags email addresses.
+var NewEmailRedactor = redact.NewEmailRedactor
+
+// NewAPIKeyRedactor flags strings matching well-known API key shapes.
+var NewAPIKeyRedactor = redact.NewAPIKeyRedactor
+
+// CreditCardRedactor flags digit runs that pass the Luhn checksum used by
+// card numbers.
+type CreditCardRedactor = redact.CreditCardRedactor
+
+// EntropySecretRedactor flags long tokens whose character distribution
+// looks random (high Shannon entropy per character), catching secrets that
+// don't match any known vendor prefix.
+type EntropySecretRedactor = redact.EntropySecretRedactor
+
+// NewEntropySecretRedactor returns an EntropySecretRedactor with reasonable
+// defaults.
+var NewEntropySecretRedactor = redact.NewEntropySecretRedactor
+
+// DLPRedactor adapts an external DLP service into the Redactor interface.
+type DLPRedactor = redact.DLPRedactor
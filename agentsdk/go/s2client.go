@@ -101,6 +101,29 @@ func (c *S2Client) AppendEvent(ctx context.Context, streamName string, event *Ev
 	return nil
 }
 
+// EnsureStream satisfies the Sink interface by delegating to CreateStream.
+func (c *S2Client) EnsureStream(ctx context.Context, streamName string) error {
+	return c.CreateStream(ctx, streamName)
+}
+
+// Append satisfies the Sink interface, submitting events one at a time since
+// S2's append endpoint is the unit S2Client was built around.
+func (c *S2Client) Append(ctx context.Context, streamName string, events []*Event) error {
+	for _, event := range events {
+		if err := c.AppendEvent(ctx, streamName, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close satisfies the Sink interface. S2Client holds no resources beyond its
+// *http.Client, so this just releases idle connections.
+func (c *S2Client) Close(ctx context.Context) error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
 func (c *S2Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -19,9 +21,13 @@ type Session struct {
 	AgentName string
 	StartTime time.Time
 
-	tracer   trace.Tracer
-	s2Client *S2Client
-	config   *Config
+	tracer trace.Tracer
+	config *Config
+
+	pendingSinks []namedSink
+	walDir       string
+	producer     *producer
+	redaction    *RedactionPipeline
 
 	mu       sync.Mutex
 	eventSeq int64
@@ -41,6 +47,34 @@ func WithAgentName(name string) SessionOption {
 	}
 }
 
+// WithSink registers an additional Sink that emitted events are fanned out
+// to, alongside the S2 sink configured via Config.S2Endpoint. Sinks are
+// named by the order they're registered in ("sink-0", "sink-1", ...), so
+// callers that rely on WAL recovery across restarts must pass them in the
+// same order every time.
+func WithSink(sink Sink) SessionOption {
+	return func(s *Session) {
+		s.pendingSinks = append(s.pendingSinks, namedSink{
+			name: fmt.Sprintf("sink-%d", len(s.pendingSinks)),
+			sink: sink,
+		})
+	}
+}
+
+// WithRedactor installs a RedactionPipeline that scrubs every top-level
+// string value in an emitted event's Data before it's durably enqueued.
+// String values nested inside maps or slices (such as a tool call's
+// "arguments" map, or a "result" that isn't a string) are enqueued
+// unscrubbed. Matches are
+// recorded as gen_ai.redaction.* attributes on the span active in the
+// emitting call's context; if the pipeline's Policy drops the event, it is
+// never enqueued to any sink.
+func WithRedactor(pipeline *RedactionPipeline) SessionOption {
+	return func(s *Session) {
+		s.redaction = pipeline
+	}
+}
+
 func NewSession(ctx context.Context, cfg *Config, opts ...SessionOption) (*Session, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -58,11 +92,29 @@ func NewSession(ctx context.Context, cfg *Config, opts ...SessionOption) (*Sessi
 	}
 
 	if cfg.S2Endpoint != "" && cfg.S2APIKey != "" {
-		session.s2Client = NewS2Client(cfg.S2Endpoint, cfg.S2APIKey)
-		streamName := cfg.S2StreamPrefix + session.ID
-		if err := session.s2Client.CreateStream(ctx, streamName); err != nil {
-			return nil, fmt.Errorf("failed to create S2 stream: %w", err)
+		s2Client := NewS2Client(cfg.S2Endpoint, cfg.S2APIKey)
+		session.pendingSinks = append(session.pendingSinks, namedSink{name: "s2", sink: s2Client})
+	}
+
+	// Only pay for a WAL and its producer when there's actually somewhere
+	// for events to drain to; a sink-less session (e.g. OTLP-only tracing)
+	// has nothing to recover on restart and no reason to fsync every event.
+	if len(session.pendingSinks) > 0 {
+		session.walDir = filepath.Join(cfg.WALDir, session.ID)
+
+		wal, err := OpenWAL(session.walDir, defaultWALSegmentBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open session WAL: %w", err)
 		}
+
+		p, err := newProducer(wal, session.pendingSinks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start event producer: %w", err)
+		}
+		if err := p.start(session.StreamName()); err != nil {
+			return nil, fmt.Errorf("failed to start event producer: %w", err)
+		}
+		session.producer = p
 	}
 
 	session.emitEvent(ctx, &Event{
@@ -81,6 +133,13 @@ func (s *Session) StreamName() string {
 	return s.config.S2StreamPrefix + s.ID
 }
 
+// Close flushes the session-end event and blocks until the WAL has drained
+// to every sink or Config.CloseDeadline elapses, whichever comes first. If
+// the deadline elapses first, undelivered events remain durable in the WAL
+// under Config.WALDir and will be resent the next time RecoverSessions runs
+// against that directory. If the drain completes in full, this session's
+// WAL directory is removed rather than left for RecoverSessions to clean up
+// later.
 func (s *Session) Close(ctx context.Context) error {
 	s.emitEvent(ctx, &Event{
 		Type:      EventSessionEnd,
@@ -89,6 +148,24 @@ func (s *Session) Close(ctx context.Context) error {
 			"duration_ms": time.Since(s.StartTime).Milliseconds(),
 		},
 	})
+
+	if s.producer == nil {
+		return nil
+	}
+
+	drained := false
+	select {
+	case <-waitDrained(s.producer, s.config.CloseDeadline):
+		drained = s.producer.allDrained()
+	case <-ctx.Done():
+	}
+
+	if err := s.producer.close(ctx, s.config.CloseDeadline); err != nil {
+		return err
+	}
+	if drained {
+		return os.RemoveAll(s.walDir)
+	}
 	return nil
 }
 
@@ -99,12 +176,57 @@ func (s *Session) emitEvent(ctx context.Context, event *Event) {
 	event.SessionID = s.ID
 	s.mu.Unlock()
 
-	if s.s2Client != nil {
-		go func() {
-			streamName := s.config.S2StreamPrefix + s.ID
-			_ = s.s2Client.AppendEvent(context.Background(), streamName, event)
-		}()
+	if s.redaction != nil && len(event.Data) > 0 {
+		count, drop, kinds := s.redaction.ScrubAttributes(ctx, event.Data)
+		if count > 0 {
+			recordRedactionAttributes(ctx, count, kinds)
+		}
+		if drop {
+			return
+		}
+	}
+
+	if s.producer == nil {
+		return
 	}
+	_ = s.producer.enqueue(event)
+}
+
+// recordRedactionAttributes marks the span active in ctx with what a
+// RedactionPipeline found, so downstream consumers know the event's
+// content was modified and why.
+func recordRedactionAttributes(ctx context.Context, count int, kinds []RedactionKind) {
+	kindStrs := make([]string, len(kinds))
+	for i, k := range kinds {
+		kindStrs[i] = string(k)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool("gen_ai.redaction.applied", true),
+		attribute.Int("gen_ai.redaction.count", count),
+		attribute.StringSlice("gen_ai.redaction.kinds", kindStrs),
+	)
+}
+
+// redactForSpan scrubs value the same way emitEvent scrubs an event's Data,
+// before it's attached to a span as an attribute or event. SetupTracing
+// ships spans over OTLP independent of any configured Sink, so the prompt,
+// tool call, and response content these functions put on spans needs the
+// same pass through WithRedactor as the WAL/Sink copy, or a redactor
+// configuration only looks like it's scrubbing sensitive content. If the
+// Policy drops the value, an empty string is returned in its place.
+func (s *Session) redactForSpan(ctx context.Context, value string) string {
+	if s.redaction == nil || value == "" {
+		return value
+	}
+	scrubbed, drop, kinds := s.redaction.Scrub(ctx, value)
+	if len(kinds) > 0 {
+		recordRedactionAttributes(ctx, 1, kinds)
+	}
+	if drop {
+		return ""
+	}
+	return scrubbed
 }
 
 func (s *Session) StartAgentInvocation(ctx context.Context, input string) (*AgentInvocation, context.Context) {
@@ -116,8 +238,7 @@ func (s *Session) StartAgentInvocation(ctx context.Context, input string) (*Agen
 		attribute.String("gen_ai.operation.name", "invoke_agent"),
 	)
 
-	inputMsgs, _ := json.Marshal([]map[string]string{{"role": "user", "content": input}})
-	span.SetAttributes(attribute.String("gen_ai.input.messages", string(inputMsgs)))
+	addGenAIMessageEvent(span, "gen_ai.user.message", "user", s.redactForSpan(ctx, input))
 
 	inv := &AgentInvocation{
 		ID:        uuid.New().String(),
@@ -148,8 +269,7 @@ type AgentInvocation struct {
 }
 
 func (inv *AgentInvocation) End(ctx context.Context, output string) {
-	outputMsgs, _ := json.Marshal([]map[string]string{{"role": "assistant", "content": output}})
-	inv.span.SetAttributes(attribute.String("gen_ai.output.messages", string(outputMsgs)))
+	addGenAIMessageEvent(inv.span, "gen_ai.assistant.message", "assistant", inv.session.redactForSpan(ctx, output))
 	inv.span.End()
 
 	inv.session.emitEvent(ctx, &Event{
@@ -174,7 +294,7 @@ func (inv *AgentInvocation) StartToolCall(ctx context.Context, toolName string,
 		attribute.String("gen_ai.operation.name", "execute_tool"),
 		attribute.String("gen_ai.tool.name", toolName),
 		attribute.String("gen_ai.tool.call.id", toolCallID),
-		attribute.String("gen_ai.tool.call.arguments", string(argsJSON)),
+		attribute.String("gen_ai.tool.call.arguments", inv.session.redactForSpan(ctx, string(argsJSON))),
 	)
 
 	tc := &ToolCall{
@@ -211,7 +331,12 @@ type ToolCall struct {
 
 func (tc *ToolCall) End(ctx context.Context, result interface{}, err error) {
 	resultJSON, _ := json.Marshal(result)
-	tc.span.SetAttributes(attribute.String("gen_ai.tool.call.result", string(resultJSON)))
+	redactedResult := tc.invocation.session.redactForSpan(ctx, string(resultJSON))
+	tc.span.SetAttributes(attribute.String("gen_ai.tool.call.result", redactedResult))
+	addGenAIMessageEvent(tc.span, "gen_ai.tool.message", "tool", redactedResult,
+		attribute.String("gen_ai.tool.call.id", tc.ID),
+		attribute.String("gen_ai.tool.name", tc.Name),
+	)
 
 	status := "success"
 	if err != nil {
@@ -234,15 +359,35 @@ func (tc *ToolCall) End(ctx context.Context, result interface{}, err error) {
 	})
 }
 
-func (inv *AgentInvocation) StartLLMCall(ctx context.Context, provider, model string) (*LLMCall, context.Context) {
+// LLMRequestParams carries the optional request-time sampling settings an
+// LLM call was invoked with. A field left at its zero value is not recorded,
+// since 0 is not a meaningful temperature, top_p, or max_tokens for any
+// provider this SDK targets.
+type LLMRequestParams struct {
+	Temperature float64
+	TopP        float64
+	MaxTokens   int64
+}
+
+func (inv *AgentInvocation) StartLLMCall(ctx context.Context, provider, model string, params LLMRequestParams) (*LLMCall, context.Context) {
 	ctx, span := inv.session.tracer.Start(ctx, "llm.generate")
 
-	span.SetAttributes(
+	attrs := []attribute.KeyValue{
 		attribute.String("gen_ai.conversation.id", inv.session.ID),
 		attribute.String("gen_ai.operation.name", "chat"),
 		attribute.String("gen_ai.provider.name", provider),
 		attribute.String("gen_ai.request.model", model),
-	)
+	}
+	if params.Temperature != 0 {
+		attrs = append(attrs, attribute.Float64("gen_ai.request.temperature", params.Temperature))
+	}
+	if params.TopP != 0 {
+		attrs = append(attrs, attribute.Float64("gen_ai.request.top_p", params.TopP))
+	}
+	if params.MaxTokens != 0 {
+		attrs = append(attrs, attribute.Int64("gen_ai.request.max_tokens", params.MaxTokens))
+	}
+	span.SetAttributes(attrs...)
 
 	llm := &LLMCall{
 		ID:         uuid.New().String(),
@@ -276,11 +421,29 @@ type LLMCall struct {
 	startTime  time.Time
 }
 
-func (llm *LLMCall) End(ctx context.Context, response string, inputTokens, outputTokens int64) {
+// End records the model's response and token usage, closing the LLM call's
+// span. responseID is the provider's identifier for this specific response
+// (e.g. OpenAI's chatcmpl-* ID), left empty if the provider doesn't return
+// one. responseModel is the model identifier the provider actually served
+// the request with, which may differ from the requested model; finishReason
+// is the provider's stop reason (e.g. "stop", "length", "tool_calls").
+func (llm *LLMCall) End(ctx context.Context, response, responseID, responseModel, finishReason string, inputTokens, outputTokens int64) {
 	llm.span.SetAttributes(
+		attribute.String("gen_ai.response.model", responseModel),
+		attribute.String("gen_ai.response.finish_reasons", finishReason),
 		attribute.Int64("gen_ai.usage.input_tokens", inputTokens),
 		attribute.Int64("gen_ai.usage.output_tokens", outputTokens),
 	)
+	if responseID != "" {
+		llm.span.SetAttributes(attribute.String("gen_ai.response.id", responseID))
+	}
+	if cost, ok := llm.invocation.session.config.PricingTable.Cost(llm.Provider, llm.Model, inputTokens, outputTokens); ok {
+		llm.span.SetAttributes(attribute.Float64("gen_ai.usage.cost", cost))
+	}
+	addGenAIMessageEvent(llm.span, "gen_ai.choice", "assistant", llm.invocation.session.redactForSpan(ctx, response),
+		attribute.Int("gen_ai.choice.index", 0),
+		attribute.String("gen_ai.response.finish_reasons", finishReason),
+	)
 	llm.span.End()
 
 	llm.invocation.session.emitEvent(ctx, &Event{
@@ -296,4 +459,16 @@ func (llm *LLMCall) End(ctx context.Context, response string, inputTokens, outpu
 	})
 }
 
+// addGenAIMessageEvent records one GenAI semantic-convention span event
+// (gen_ai.user.message, gen_ai.assistant.message, gen_ai.tool.message, or
+// gen_ai.choice), carrying its role and content as structured attributes
+// rather than a single JSON-encoded blob.
+func addGenAIMessageEvent(span trace.Span, name, role, content string, extra ...attribute.KeyValue) {
+	attrs := append([]attribute.KeyValue{
+		attribute.String("gen_ai.message.role", role),
+		attribute.String("gen_ai.message.content", content),
+	}, extra...)
+	span.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
 
@@ -0,0 +1,138 @@
+package agentsdk
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestSession builds a Session with no producer/WAL (no sinks), backed by
+// an in-memory span exporter, so span content can be inspected directly
+// without standing up a collector.
+func newTestSession(t *testing.T, redaction *RedactionPipeline) (*Session, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	return &Session{
+		ID:        "test-session",
+		AgentID:   "agent-1",
+		AgentName: "TestAgent",
+		StartTime: time.Now(),
+		tracer:    tp.Tracer("agentsdk-test"),
+		config:    &Config{PricingTable: DefaultPricingTable},
+		redaction: redaction,
+	}, exporter
+}
+
+func eventAttr(stub tracetest.SpanStub, eventName, key string) (string, bool) {
+	for _, e := range stub.Events {
+		if e.Name != eventName {
+			continue
+		}
+		for _, a := range e.Attributes {
+			if string(a.Key) == key {
+				return a.Value.AsString(), true
+			}
+		}
+	}
+	return "", false
+}
+
+func spanAttr(stub tracetest.SpanStub, key string) (string, bool) {
+	for _, a := range stub.Attributes {
+		if string(a.Key) == key {
+			return a.Value.AsString(), true
+		}
+	}
+	return "", false
+}
+
+func TestAgentInvocationSpansRedactMessageContent(t *testing.T) {
+	pipeline := NewRedactionPipeline(MarkerPolicy{}, NewEmailRedactor())
+	session, exporter := newTestSession(t, pipeline)
+	ctx := context.Background()
+
+	inv, ctx := session.StartAgentInvocation(ctx, "contact me at jane@example.com")
+	inv.End(ctx, "sure, I'll email jane@example.com")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	got, ok := eventAttr(spans[0], "gen_ai.user.message", "gen_ai.message.content")
+	if !ok || strings.Contains(got, "jane@example.com") {
+		t.Errorf("gen_ai.user.message content = %q, ok=%v, want email redacted", got, ok)
+	}
+
+	got, ok = eventAttr(spans[0], "gen_ai.assistant.message", "gen_ai.message.content")
+	if !ok || strings.Contains(got, "jane@example.com") {
+		t.Errorf("gen_ai.assistant.message content = %q, ok=%v, want email redacted", got, ok)
+	}
+}
+
+func TestToolCallSpansRedactArgsAndResult(t *testing.T) {
+	pipeline := NewRedactionPipeline(MarkerPolicy{}, NewEmailRedactor())
+	session, exporter := newTestSession(t, pipeline)
+	ctx := context.Background()
+
+	inv, ctx := session.StartAgentInvocation(ctx, "hello")
+	tc, ctx := inv.StartToolCall(ctx, "send_email", map[string]interface{}{"to": "jane@example.com"})
+	tc.End(ctx, map[string]interface{}{"status": "sent to jane@example.com"}, nil)
+	inv.End(ctx, "done")
+
+	spans := exporter.GetSpans()
+	var toolSpan tracetest.SpanStub
+	for _, s := range spans {
+		if s.Name == "tool.send_email" {
+			toolSpan = s
+		}
+	}
+	if toolSpan.Name == "" {
+		t.Fatalf("tool span not found among %d spans", len(spans))
+	}
+
+	argsAttr, ok := spanAttr(toolSpan, "gen_ai.tool.call.arguments")
+	if !ok || strings.Contains(argsAttr, "jane@example.com") {
+		t.Errorf("gen_ai.tool.call.arguments = %q, ok=%v, want email redacted", argsAttr, ok)
+	}
+
+	resultAttr, ok := spanAttr(toolSpan, "gen_ai.tool.call.result")
+	if !ok || strings.Contains(resultAttr, "jane@example.com") {
+		t.Errorf("gen_ai.tool.call.result = %q, ok=%v, want email redacted", resultAttr, ok)
+	}
+}
+
+func TestLLMCallSpanRedactsResponse(t *testing.T) {
+	pipeline := NewRedactionPipeline(MarkerPolicy{}, NewEmailRedactor())
+	session, exporter := newTestSession(t, pipeline)
+	ctx := context.Background()
+
+	inv, ctx := session.StartAgentInvocation(ctx, "hello")
+	llm, ctx := inv.StartLLMCall(ctx, "openai", "gpt-4", LLMRequestParams{})
+	llm.End(ctx, "reach me at jane@example.com", "resp-1", "gpt-4", "stop", 10, 5)
+	inv.End(ctx, "done")
+
+	spans := exporter.GetSpans()
+	var llmSpan tracetest.SpanStub
+	for _, s := range spans {
+		if s.Name == "llm.generate" {
+			llmSpan = s
+		}
+	}
+	if llmSpan.Name == "" {
+		t.Fatalf("llm span not found among %d spans", len(spans))
+	}
+
+	got, ok := eventAttr(llmSpan, "gen_ai.choice", "gen_ai.message.content")
+	if !ok || strings.Contains(got, "jane@example.com") {
+		t.Errorf("gen_ai.choice content = %q, ok=%v, want email redacted", got, ok)
+	}
+}
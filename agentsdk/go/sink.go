@@ -0,0 +1,88 @@
+package agentsdk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink is a destination for emitted events. Session fans events out to every
+// configured Sink; S2Client is the default implementation, but the SDK also
+// ships stdout/file, Kafka, NATS JetStream, and gRPC sinks so agents can be
+// observed without the S2-hosted service.
+type Sink interface {
+	// EnsureStream prepares streamID to receive events, creating it if the
+	// underlying transport requires that.
+	EnsureStream(ctx context.Context, streamID string) error
+	// Append writes events to streamID. Implementations may batch internally
+	// but must preserve the order events were given in.
+	Append(ctx context.Context, streamID string, events []*Event) error
+	// Close releases any resources held by the sink. It must be safe to call
+	// more than once.
+	Close(ctx context.Context) error
+}
+
+// WriterSink writes newline-delimited JSON events to an io.Writer. It backs
+// both NewStdoutSink and NewFileSink.
+type WriterSink struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+}
+
+// NewStdoutSink writes each event as a JSON line to os.Stdout. It is useful
+// for local development without a running collector or S2 stream.
+func NewStdoutSink() *WriterSink {
+	return &WriterSink{w: bufio.NewWriter(os.Stdout)}
+}
+
+// NewFileSink appends each event as a JSON line to the file at path,
+// creating it if necessary.
+func NewFileSink(path string) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file: %w", err)
+	}
+	return &WriterSink{w: bufio.NewWriter(f), closer: f}, nil
+}
+
+func (s *WriterSink) EnsureStream(ctx context.Context, streamID string) error {
+	return nil
+}
+
+func (s *WriterSink) Append(ctx context.Context, streamID string, events []*Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		line, err := event.ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		if _, err := s.w.Write(line); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+		if err := s.w.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+	}
+	return s.w.Flush()
+}
+
+func (s *WriterSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if s.closer != nil {
+		err := s.closer.Close()
+		s.closer = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package agentsdk
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+)
+
+func init() {
+	encoding.RegisterCodec(grpcJSONCodec{})
+}
+
+// grpcJSONCodec lets GRPCSink speak gRPC's streaming wire format without a
+// generated protobuf client: messages are JSON-encoded, which matches
+// Event.ToJSON and keeps the sink self-contained.
+type grpcJSONCodec struct{}
+
+func (grpcJSONCodec) Name() string { return "json" }
+
+func (grpcJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (grpcJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// GRPCSink streams events to a collector's "/agentsdk.EventSink/Append"
+// client-streaming RPC.
+type GRPCSink struct {
+	conn *grpc.ClientConn
+}
+
+// NewGRPCSink dials target and returns a sink that streams events to it.
+// Set insecureTransport to true for plaintext connections (local dev);
+// otherwise the system's default TLS credentials are used.
+func NewGRPCSink(target string, insecureTransport bool) (*GRPCSink, error) {
+	opts := []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.CallContentSubtype("json"))}
+	if insecureTransport {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial grpc sink target: %w", err)
+	}
+	return &GRPCSink{conn: conn}, nil
+}
+
+func (s *GRPCSink) EnsureStream(ctx context.Context, streamID string) error {
+	return nil
+}
+
+func (s *GRPCSink) Append(ctx context.Context, streamID string, events []*Event) error {
+	stream, err := s.conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true}, "/agentsdk.EventSink/Append")
+	if err != nil {
+		return fmt.Errorf("failed to open grpc stream: %w", err)
+	}
+
+	for _, event := range events {
+		envelope := &grpcEventEnvelope{StreamID: streamID, Event: event}
+		if err := stream.SendMsg(envelope); err != nil {
+			return fmt.Errorf("failed to send event over grpc: %w", err)
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return fmt.Errorf("failed to close grpc stream: %w", err)
+	}
+
+	var ack grpcAck
+	if err := stream.RecvMsg(&ack); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to receive grpc ack: %w", err)
+	}
+	return nil
+}
+
+func (s *GRPCSink) Close(ctx context.Context) error {
+	return s.conn.Close()
+}
+
+type grpcEventEnvelope struct {
+	StreamID string `json:"stream_id"`
+	Event    *Event `json:"event"`
+}
+
+type grpcAck struct {
+	Acked int `json:"acked"`
+}
@@ -0,0 +1,83 @@
+package agentsdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink publishes events to a Kafka topic, one writer per stream ID so
+// each agent session's events land on their own partition key.
+type KafkaSink struct {
+	brokers []string
+
+	mu      sync.Mutex
+	writers map[string]*kafka.Writer
+}
+
+// NewKafkaSink creates a sink that publishes to the given Kafka brokers.
+// Each streamID passed to Append/EnsureStream is used as the topic name.
+func NewKafkaSink(brokers []string) *KafkaSink {
+	return &KafkaSink{
+		brokers: brokers,
+		writers: make(map[string]*kafka.Writer),
+	}
+}
+
+func (s *KafkaSink) writerFor(streamID string) *kafka.Writer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w, ok := s.writers[streamID]; ok {
+		return w
+	}
+
+	w := &kafka.Writer{
+		Addr:     kafka.TCP(s.brokers...),
+		Topic:    streamID,
+		Balancer: &kafka.LeastBytes{},
+	}
+	s.writers[streamID] = w
+	return w
+}
+
+func (s *KafkaSink) EnsureStream(ctx context.Context, streamID string) error {
+	s.writerFor(streamID)
+	return nil
+}
+
+func (s *KafkaSink) Append(ctx context.Context, streamID string, events []*Event) error {
+	w := s.writerFor(streamID)
+
+	msgs := make([]kafka.Message, len(events))
+	for i, event := range events {
+		body, err := event.ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		msgs[i] = kafka.Message{
+			Key:   []byte(event.SessionID),
+			Value: body,
+		}
+	}
+
+	if err := w.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("failed to write kafka messages: %w", err)
+	}
+	return nil
+}
+
+func (s *KafkaSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	for streamID, w := range s.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close kafka writer for %s: %w", streamID, err)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,63 @@
+package agentsdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSSink publishes events to a NATS JetStream stream, using streamID as
+// both the JetStream stream name and the subject events are published on.
+type NATSSink struct {
+	nc *nats.Conn
+	js jetstream.JetStream
+}
+
+// NewNATSSink connects to the given NATS server URL and returns a sink
+// backed by JetStream. Callers own the returned sink and must Close it to
+// release the underlying connection.
+func NewNATSSink(url string) (*NATSSink, error) {
+	nc, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	return &NATSSink{nc: nc, js: js}, nil
+}
+
+func (s *NATSSink) EnsureStream(ctx context.Context, streamID string) error {
+	_, err := s.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     streamID,
+		Subjects: []string{streamID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure jetstream stream %s: %w", streamID, err)
+	}
+	return nil
+}
+
+func (s *NATSSink) Append(ctx context.Context, streamID string, events []*Event) error {
+	for _, event := range events {
+		body, err := event.ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		if _, err := s.js.Publish(ctx, streamID, body); err != nil {
+			return fmt.Errorf("failed to publish to jetstream: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *NATSSink) Close(ctx context.Context) error {
+	s.nc.Close()
+	return nil
+}
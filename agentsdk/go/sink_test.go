@@ -0,0 +1,158 @@
+package agentsdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestWriterSinkAppendWritesNewlineDelimitedJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+
+	events := []*Event{
+		{Type: EventCustom, Timestamp: time.Now(), Data: map[string]interface{}{"i": 0}},
+		{Type: EventCustom, Timestamp: time.Now(), Data: map[string]interface{}{"i": 1}},
+	}
+	if err := sink.Append(context.Background(), "stream-1", events); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	var got []map[string]interface{}
+	for scanner.Scan() {
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &decoded); err != nil {
+			t.Fatalf("line %q did not decode as JSON: %v", scanner.Text(), err)
+		}
+		got = append(got, decoded)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scan error = %v", err)
+	}
+
+	if len(got) != len(events) {
+		t.Fatalf("got %d lines, want %d", len(got), len(events))
+	}
+	for i, decoded := range got {
+		data := decoded["data"].(map[string]interface{})
+		if int(data["i"].(float64)) != i {
+			t.Errorf("line %d: data.i = %v, want %d", i, data["i"], i)
+		}
+	}
+}
+
+func TestWriterSinkCloseIsSafeToCallTwice(t *testing.T) {
+	sink, err := NewFileSink(filepath.Join(t.TempDir(), "events.jsonl"))
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("first Close() error = %v", err)
+	}
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+}
+
+// grpcEventSinkServer is a minimal stand-in for the collector-side
+// "/agentsdk.EventSink/Append" handler GRPCSink talks to: it decodes every
+// envelope off the client stream using the same grpcJSONCodec and records
+// them for the test to inspect, then acks once the client half-closes.
+type grpcEventSinkServer struct {
+	received []grpcEventEnvelope
+}
+
+func (s *grpcEventSinkServer) append(srv interface{}, stream grpc.ServerStream) error {
+	for {
+		var envelope grpcEventEnvelope
+		if err := stream.RecvMsg(&envelope); err != nil {
+			break
+		}
+		s.received = append(s.received, envelope)
+	}
+	return stream.SendMsg(&grpcAck{Acked: len(s.received)})
+}
+
+func (s *grpcEventSinkServer) serviceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "agentsdk.EventSink",
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "Append",
+				Handler:       s.append,
+				ClientStreams: true,
+			},
+		},
+	}
+}
+
+func TestGRPCSinkAppendStreamsEnvelopesAndReceivesAck(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	defer listener.Close()
+
+	server := grpc.NewServer()
+	fakeSink := &grpcEventSinkServer{}
+	server.RegisterService(fakeSink.serviceDesc(), nil)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype("json")),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	sink := &GRPCSink{conn: conn}
+	events := []*Event{
+		{Type: EventCustom, Timestamp: time.Now(), Data: map[string]interface{}{"i": 0}},
+		{Type: EventCustom, Timestamp: time.Now(), Data: map[string]interface{}{"i": 1}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := sink.Append(ctx, "stream-1", events); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	if len(fakeSink.received) != len(events) {
+		t.Fatalf("server received %d envelopes, want %d", len(fakeSink.received), len(events))
+	}
+	for i, envelope := range fakeSink.received {
+		if envelope.StreamID != "stream-1" {
+			t.Errorf("envelope %d: StreamID = %q, want %q", i, envelope.StreamID, "stream-1")
+		}
+		if envelope.Event.Data["i"].(float64) != float64(i) {
+			t.Errorf("envelope %d: Data[i] = %v, want %d", i, envelope.Event.Data["i"], i)
+		}
+	}
+}
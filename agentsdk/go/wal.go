@@ -0,0 +1,491 @@
+package agentsdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultWALSegmentBytes bounds how large a single WAL segment file grows
+// before a new one is rotated in.
+const defaultWALSegmentBytes = 8 * 1024 * 1024
+
+// walSyncTimeout bounds how long Append waits for fsync to complete. Append
+// is called synchronously from Session.emitEvent, so a stalled disk must not
+// be able to stall the calling agent code indefinitely; past this deadline,
+// Append returns an error (the record was written but is not yet confirmed
+// durable) instead of blocking forever.
+const walSyncTimeout = 5 * time.Second
+
+const walSegmentPrefix = "seg-"
+const walSegmentSuffix = ".log"
+const walAckPrefix = "ack-"
+
+// walRecord is the on-disk representation of one queued event. Seq is
+// monotonic per WAL and lets a restarted process tell which records a
+// consumer already acked.
+type walRecord struct {
+	Seq   int64  `json:"seq"`
+	Event *Event `json:"event"`
+}
+
+// WAL is a segmented, append-only write-ahead log of events for a single
+// session. Session.emitEvent appends to it synchronously so an event is
+// durable on disk before the call returns; per-sink cursors then tail the
+// log independently, each retrying until it acks the records it has
+// delivered. Segments are deleted once every registered cursor has acked
+// past them.
+type WAL struct {
+	dir             string
+	maxSegmentBytes int64
+
+	mu        sync.Mutex
+	nextSeq   int64
+	segments  []int64
+	curSeg    int64
+	curFile   *os.File
+	curWriter *bufio.Writer
+	curBytes  int64
+
+	notifyMu sync.Mutex
+	notifyCh chan struct{}
+
+	consumersMu sync.Mutex
+	consumers   map[string]int64
+}
+
+// OpenWAL opens (or creates) the WAL directory for a session, replaying any
+// existing segments so nextSeq and segment bookkeeping continue where a
+// prior process left off.
+func OpenWAL(dir string, maxSegmentBytes int64) (*WAL, error) {
+	if maxSegmentBytes <= 0 {
+		maxSegmentBytes = defaultWALSegmentBytes
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create WAL dir: %w", err)
+	}
+
+	w := &WAL{
+		dir:             dir,
+		maxSegmentBytes: maxSegmentBytes,
+		notifyCh:        make(chan struct{}),
+		consumers:       make(map[string]int64),
+	}
+
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	w.segments = segments
+
+	if len(segments) == 0 {
+		w.curSeg = 1
+		w.segments = []int64{1}
+	} else {
+		w.curSeg = segments[len(segments)-1]
+		w.nextSeq, err = lastSeqIn(segmentPath(dir, w.curSeg))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(segmentPath(dir, w.curSeg), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open WAL segment: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat WAL segment: %w", err)
+	}
+
+	w.curFile = f
+	w.curWriter = bufio.NewWriter(f)
+	w.curBytes = info.Size()
+
+	return w, nil
+}
+
+func segmentPath(dir string, seg int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%010d%s", walSegmentPrefix, seg, walSegmentSuffix))
+}
+
+func listSegments(dir string) ([]int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list WAL dir: %w", err)
+	}
+
+	var segments []int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, walSegmentPrefix) || !strings.HasSuffix(name, walSegmentSuffix) {
+			continue
+		}
+		numStr := strings.TrimSuffix(strings.TrimPrefix(name, walSegmentPrefix), walSegmentSuffix)
+		num, err := strconv.ParseInt(numStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, num)
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+	return segments, nil
+}
+
+func lastSeqIn(path string) (int64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open WAL segment: %w", err)
+	}
+	defer f.Close()
+
+	var last int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec walRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		last = rec.Seq
+	}
+	return last, scanner.Err()
+}
+
+// Append durably writes event to the WAL and returns its sequence number.
+func (w *WAL) Append(event *Event) (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.nextSeq++
+	rec := walRecord{Seq: w.nextSeq, Event: event}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal WAL record: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := w.curWriter.Write(line); err != nil {
+		return 0, fmt.Errorf("failed to write WAL record: %w", err)
+	}
+	if err := w.curWriter.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to flush WAL record: %w", err)
+	}
+	if err := w.syncWithTimeout(); err != nil {
+		return 0, fmt.Errorf("failed to sync WAL record: %w", err)
+	}
+	w.curBytes += int64(len(line))
+
+	if w.curBytes >= w.maxSegmentBytes {
+		if err := w.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	w.notify()
+	return rec.Seq, nil
+}
+
+// syncWithTimeout fsyncs the active segment file, bounding how long the
+// caller can be blocked if the underlying disk has stalled. curFile.Sync()
+// itself can't be canceled, so a timed-out sync leaves its goroutine running
+// in the background until the syscall eventually returns.
+func (w *WAL) syncWithTimeout() error {
+	done := make(chan error, 1)
+	go func() { done <- w.curFile.Sync() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(walSyncTimeout):
+		return fmt.Errorf("WAL sync did not complete within %s", walSyncTimeout)
+	}
+}
+
+func (w *WAL) rotateLocked() error {
+	if err := w.curFile.Close(); err != nil {
+		return fmt.Errorf("failed to close WAL segment: %w", err)
+	}
+
+	w.curSeg++
+	w.segments = append(w.segments, w.curSeg)
+
+	f, err := os.OpenFile(segmentPath(w.dir, w.curSeg), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create WAL segment: %w", err)
+	}
+	w.curFile = f
+	w.curWriter = bufio.NewWriter(f)
+	w.curBytes = 0
+	return nil
+}
+
+func (w *WAL) notify() {
+	w.notifyMu.Lock()
+	close(w.notifyCh)
+	w.notifyCh = make(chan struct{})
+	w.notifyMu.Unlock()
+}
+
+func (w *WAL) waitChan() chan struct{} {
+	w.notifyMu.Lock()
+	defer w.notifyMu.Unlock()
+	return w.notifyCh
+}
+
+// isAtTail reports whether (seg, offset) points at the end of the WAL, i.e.
+// there is nothing left to read from that position.
+func (w *WAL) isAtTail(seg, offset int64) bool {
+	w.mu.Lock()
+	curSeg := w.curSeg
+	w.mu.Unlock()
+
+	if seg != curSeg {
+		return false
+	}
+	info, err := os.Stat(segmentPath(w.dir, seg))
+	if err != nil {
+		return offset == 0
+	}
+	return offset >= info.Size()
+}
+
+// currentSegment returns the segment number currently being written to.
+func (w *WAL) currentSegment() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.curSeg
+}
+
+// SizeBytes returns the total size of all segment files still on disk.
+func (w *WAL) SizeBytes() int64 {
+	w.mu.Lock()
+	segments := append([]int64(nil), w.segments...)
+	dir := w.dir
+	w.mu.Unlock()
+
+	var total int64
+	for _, seg := range segments {
+		if info, err := os.Stat(segmentPath(dir, seg)); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// OpenCursor returns a named tailer over the WAL, resuming from wherever
+// that name last acked (including across process restarts, since the ack
+// position is itself persisted to disk).
+func (w *WAL) OpenCursor(name string) (*walCursor, error) {
+	seg, offset, err := readAckFile(w.ackPath(name))
+	if err != nil {
+		return nil, err
+	}
+	if seg == 0 {
+		w.mu.Lock()
+		seg = w.segments[0]
+		w.mu.Unlock()
+	}
+
+	w.consumersMu.Lock()
+	w.consumers[name] = seg
+	w.consumersMu.Unlock()
+
+	return &walCursor{wal: w, name: name, seg: seg, offset: offset}, nil
+}
+
+func (w *WAL) ackPath(name string) string {
+	return filepath.Join(w.dir, walAckPrefix+name)
+}
+
+func readAckFile(path string) (seg, offset int64, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read WAL ack file: %w", err)
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(string(data)), ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, nil
+	}
+	seg, err1 := strconv.ParseInt(parts[0], 10, 64)
+	offset, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, nil
+	}
+	return seg, offset, nil
+}
+
+// recordAck persists a consumer's progress and garbage-collects any
+// segments every registered consumer has fully acked past.
+func (w *WAL) recordAck(name string, seg, offset int64) error {
+	path := w.ackPath(name)
+	tmp := path + ".tmp"
+	content := fmt.Sprintf("%d:%d", seg, offset)
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write WAL ack file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to commit WAL ack file: %w", err)
+	}
+
+	w.consumersMu.Lock()
+	w.consumers[name] = seg
+	minSeg := seg
+	for _, s := range w.consumers {
+		if s < minSeg {
+			minSeg = s
+		}
+	}
+	w.consumersMu.Unlock()
+
+	w.gc(minSeg)
+	return nil
+}
+
+// gc removes segments strictly older than keepFrom, which every registered
+// consumer has already acked past.
+func (w *WAL) gc(keepFrom int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var remaining []int64
+	for _, seg := range w.segments {
+		if seg < keepFrom && seg != w.curSeg {
+			_ = os.Remove(segmentPath(w.dir, seg))
+			continue
+		}
+		remaining = append(remaining, seg)
+	}
+	w.segments = remaining
+}
+
+// Close flushes and closes the active segment file. Consumers and their ack
+// files are left on disk so a future OpenWAL/OpenCursor can resume.
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.curWriter.Flush(); err != nil {
+		return err
+	}
+	return w.curFile.Close()
+}
+
+// walCursor tails a WAL from a persisted (segment, offset) position. seg and
+// offset are mutated by Next, which runs on the tailing goroutine, but are
+// also read by allDrained/isAtTail from the goroutine driving Session.Close
+// or RecoverSessions; posMu guards both fields against that concurrent read.
+type walCursor struct {
+	wal  *WAL
+	name string
+
+	posMu  sync.Mutex
+	seg    int64
+	offset int64
+}
+
+// position returns the cursor's current (segment, offset), safe to call
+// concurrently with Next.
+func (c *walCursor) position() (seg, offset int64) {
+	c.posMu.Lock()
+	defer c.posMu.Unlock()
+	return c.seg, c.offset
+}
+
+// Next blocks until a record is available after the cursor's current
+// position, or ctx is done.
+func (c *walCursor) Next(ctx context.Context) (*walRecord, error) {
+	for {
+		seg, offset := c.position()
+
+		// wait must be captured before checking for new data: if an Append
+		// (and its notify) lands between the check below and a waitChan()
+		// call made after it, the cursor would block on a channel that has
+		// no way of being woken for the record that was just written.
+		// Capturing it first means that race instead closes the very
+		// channel being waited on, so the select below wakes immediately.
+		wait := c.wal.waitChan()
+
+		rec, nextOffset, err := readRecordAt(segmentPath(c.wal.dir, seg), offset)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			c.posMu.Lock()
+			c.offset = nextOffset
+			c.posMu.Unlock()
+			return rec, nil
+		}
+
+		if seg < c.wal.currentSegment() {
+			c.posMu.Lock()
+			c.seg++
+			c.offset = 0
+			c.posMu.Unlock()
+			continue
+		}
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Ack persists that this cursor has successfully delivered the record read
+// up to its current position.
+func (c *walCursor) Ack() error {
+	seg, offset := c.position()
+	return c.wal.recordAck(c.name, seg, offset)
+}
+
+func readRecordAt(path string, offset int64) (*walRecord, int64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, offset, nil
+	}
+	if err != nil {
+		return nil, offset, fmt.Errorf("failed to open WAL segment: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, fmt.Errorf("failed to seek WAL segment: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		if err == io.EOF {
+			return nil, offset, nil
+		}
+		return nil, offset, fmt.Errorf("failed to read WAL segment: %w", err)
+	}
+
+	var rec walRecord
+	if err := json.Unmarshal(bytes.TrimRight(line, "\n"), &rec); err != nil {
+		return nil, offset, fmt.Errorf("failed to decode WAL record: %w", err)
+	}
+	return &rec, offset + int64(len(line)), nil
+}
@@ -0,0 +1,158 @@
+// Command tailgateway proxies a live or historical agentsdk session stream
+// to browser clients, as newline-delimited JSON over a plain HTTP response
+// or as WebSocket text frames, so a UI can watch an agent run without
+// speaking S2's API directly.
+//
+// Neither /tail/ nor /ws/ requires authentication: anyone who can reach the
+// listener and guess or observe a stream name can read that session's full
+// event history. Deploy tailgateway behind a reverse proxy or network
+// boundary that authenticates and authorizes callers; this binary does not
+// do so itself.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	agentsdk "github.com/agent-observability/agentsdk/go"
+)
+
+func main() {
+	addr := flag.String("addr", ":8089", "address to listen on")
+	s2Endpoint := flag.String("s2-endpoint", "https://api.s2.dev", "S2 endpoint to read streams from")
+	s2APIKey := flag.String("s2-api-key", "", "S2 API key")
+	flag.Parse()
+
+	client := agentsdk.NewS2Client(*s2Endpoint, *s2APIKey)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tail/", newTailHandler(client))
+	mux.HandleFunc("/ws/", newWebSocketTailHandler(client))
+
+	log.Printf("tailgateway listening on %s (s2 endpoint: %s)", *addr, *s2Endpoint)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf("tailgateway: %v", err)
+	}
+}
+
+// tailOptionsFromQuery builds TailOptions from the query parameters shared
+// by both the NDJSON and WebSocket handlers:
+//
+//	from_seq=<int>   replay after this sequence number
+//	from_time=<RFC3339> replay events at or after this timestamp
+//	follow=true      keep streaming new events instead of stopping at EOF
+func tailOptionsFromQuery(q map[string][]string) agentsdk.TailOptions {
+	opts := agentsdk.TailOptions{Follow: queryBool(q, "follow")}
+
+	if v := queryValue(q, "from_seq"); v != "" {
+		if seq, err := strconv.ParseInt(v, 10, 64); err == nil {
+			opts.FromSequence = seq
+		}
+	}
+	if v := queryValue(q, "from_time"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			opts.FromTime = t
+		}
+	}
+	return opts
+}
+
+func queryValue(q map[string][]string, key string) string {
+	if vs := q[key]; len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+func queryBool(q map[string][]string, key string) bool {
+	return strings.EqualFold(queryValue(q, key), "true")
+}
+
+// streamNameFromPath extracts the stream name after prefix, e.g. "/tail/" or
+// "/ws/", from an http.Request's URL path.
+func streamNameFromPath(path, prefix string) string {
+	return strings.TrimPrefix(path, prefix)
+}
+
+// newTailHandler streams a stream's events to the client as newline-
+// delimited JSON, flushing after each event so a follow=true request
+// behaves like `tail -f`.
+func newTailHandler(client *agentsdk.S2Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		streamName := streamNameFromPath(r.URL.Path, "/tail/")
+		if streamName == "" {
+			http.Error(w, "missing stream name", http.StatusBadRequest)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		opts := tailOptionsFromQuery(r.URL.Query())
+		reader := agentsdk.NewReader(client, streamName)
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		for tailed := range reader.Tail(r.Context(), opts) {
+			if tailed.Err != nil {
+				log.Printf("tailgateway: tail %s: %v", streamName, tailed.Err)
+				return
+			}
+			if err := enc.Encode(tailed.Event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// newWebSocketTailHandler is the same tail as newTailHandler, framed as
+// WebSocket text messages instead of a chunked HTTP body.
+func newWebSocketTailHandler(client *agentsdk.S2Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		streamName := streamNameFromPath(r.URL.Path, "/ws/")
+		if streamName == "" {
+			http.Error(w, "missing stream name", http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgradeWebSocket(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go conn.discardUntilClosed(cancel)
+
+		opts := tailOptionsFromQuery(r.URL.Query())
+		reader := agentsdk.NewReader(client, streamName)
+
+		for tailed := range reader.Tail(ctx, opts) {
+			if tailed.Err != nil {
+				log.Printf("tailgateway: tail %s: %v", streamName, tailed.Err)
+				return
+			}
+			line, err := json.Marshal(tailed.Event)
+			if err != nil {
+				continue
+			}
+			if err := conn.writeText(line); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// websocketGUID is the magic value RFC 6455 defines for computing the
+// Sec-WebSocket-Accept handshake header.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+)
+
+// maxClientFrameBytes bounds how large a client->server frame payload
+// tailgateway will allocate for. Clients never have a legitimate reason to
+// send tailgateway anything beyond a ping/close frame, so this is kept
+// small; readFrameHeader rejects anything over it before allocating, so a
+// forged length field in the frame header can't be used to make the
+// gateway allocate an attacker-chosen amount of memory.
+const maxClientFrameBytes = 4096
+
+// wsConn is a minimal RFC 6455 server connection: enough to perform the
+// handshake and write unfragmented text frames. tailgateway only pushes
+// events to the client, so there's no need for a general-purpose WebSocket
+// library; this keeps the gateway dependency-free, matching how GRPCSink
+// avoids a generated protobuf client.
+type wsConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+// upgradeWebSocket performs the WebSocket handshake on w/r and hijacks the
+// underlying connection for framed reads and writes.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key header")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection does not support hijacking")
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hijack connection: %w", err)
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n"
+	if _, err := buf.WriteString(response); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write handshake response: %w", err)
+	}
+	if err := buf.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to flush handshake response: %w", err)
+	}
+
+	return &wsConn{Conn: conn, br: buf.Reader}, nil
+}
+
+// writeText sends payload as a single unfragmented WebSocket text frame.
+func (c *wsConn) writeText(payload []byte) error {
+	header := make([]byte, 0, 10)
+	header = append(header, 0x80|wsOpText)
+
+	switch {
+	case len(payload) <= 125:
+		header = append(header, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(len(payload)))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(len(payload)))
+	}
+
+	if _, err := c.Write(header); err != nil {
+		return err
+	}
+	_, err := c.Write(payload)
+	return err
+}
+
+// discardUntilClosed reads and discards client frames (pings, close) until
+// the connection errors out or is closed, calling cancel so anything
+// blocked writing to it can unwind.
+func (c *wsConn) discardUntilClosed(cancel func()) {
+	defer cancel()
+	for {
+		opcode, _, err := c.readFrameHeader()
+		if err != nil {
+			return
+		}
+		if opcode == wsOpClose {
+			return
+		}
+	}
+}
+
+// errFrameTooLarge is returned when a client frame's declared length
+// exceeds maxClientFrameBytes, so the caller can close the connection
+// instead of trusting an attacker-controlled allocation size.
+var errFrameTooLarge = errors.New("websocket: frame length exceeds maximum")
+
+// readFrameHeader reads one client frame's header and masked payload,
+// returning the frame's opcode. Payload bytes are discarded; tailgateway
+// has no use for messages a client sends.
+func (c *wsConn) readFrameHeader() (byte, []byte, error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(c.br, head); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	if length > maxClientFrameBytes {
+		return 0, nil, errFrameTooLarge
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
@@ -23,13 +23,13 @@ func webSearch(query string) map[string]interface{} {
 	}
 }
 
-func simulateLLMResponse(prompt string) (string, int64, int64) {
+func simulateLLMResponse(prompt string) (string, string, int64, int64) {
 	time.Sleep(100 * time.Millisecond)
 	truncated := prompt
 	if len(prompt) > 50 {
 		truncated = prompt[:50] + "..."
 	}
-	return fmt.Sprintf("Response to: %s", truncated), 150, 50
+	return fmt.Sprintf("Response to: %s", truncated), "chatcmpl-sim-1", 150, 50
 }
 
 type WeatherAgent struct {
@@ -56,9 +56,13 @@ func (a *WeatherAgent) Run(ctx context.Context, userInput string) string {
 		prompt = userInput
 	}
 
-	llm, llmCtx := inv.StartLLMCall(ctx, "openai", "gpt-4")
-	response, inputTokens, outputTokens := simulateLLMResponse(prompt)
-	llm.End(llmCtx, response, inputTokens, outputTokens)
+	llm, llmCtx := inv.StartLLMCall(ctx, "openai", "gpt-4", agentsdk.LLMRequestParams{
+		Temperature: 0.7,
+		TopP:        1.0,
+		MaxTokens:   512,
+	})
+	response, responseID, inputTokens, outputTokens := simulateLLMResponse(prompt)
+	llm.End(llmCtx, response, responseID, llm.Model, "stop", inputTokens, outputTokens)
 
 	inv.End(ctx, response)
 	return response
@@ -0,0 +1,365 @@
+// Package redact implements the sensitive-content detection and redaction
+// pipeline shared by the SDK's own WAL/Sink/span path (agentsdk/go) and the
+// collector-side exporter (s2exporter), so the two don't drift out of sync
+// on what counts as an email, an API key, or a credit card number.
+package redact
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RedactionKind identifies what category of sensitive content a Redactor
+// found, and is recorded in the gen_ai.redaction.kinds attribute.
+type RedactionKind string
+
+const (
+	RedactionEmail      RedactionKind = "email"
+	RedactionCreditCard RedactionKind = "credit_card"
+	RedactionAPIKey     RedactionKind = "api_key"
+	RedactionSecret     RedactionKind = "secret"
+	RedactionDLP        RedactionKind = "dlp"
+)
+
+// Match is one span of sensitive content a Redactor found within a string,
+// identified by byte offset so a Policy can replace or hash just that span.
+type Match struct {
+	Kind  RedactionKind
+	Start int
+	End   int
+}
+
+// Redactor finds sensitive content within a string value. Built-in
+// implementations are regex- or entropy-based; DLPRedactor adapts an
+// external scanning service into the same interface. ctx carries whatever
+// caller context is available (a span's request context on the SDK side,
+// context.Background() from the collector's processor path), and may be
+// ignored by implementations that don't need it.
+type Redactor interface {
+	Detect(ctx context.Context, value string) []Match
+}
+
+// Policy decides what to do with the matches a Redactor found: replace them
+// in place (e.g. with a "[REDACTED:<kind>]" marker or a hash) or drop the
+// event carrying them entirely.
+type Policy interface {
+	// Apply returns the value to use in place of the original (ignored if
+	// drop is true) and whether the event carrying value should be dropped.
+	Apply(value string, matches []Match) (result string, drop bool)
+}
+
+// RedactionPipeline runs a set of Redactors over string content and lets a
+// single Policy decide what happens to whatever they find. Install one on a
+// Session via agentsdk.WithRedactor, or on an EventConverter via
+// s2exporter.WithRedaction.
+type RedactionPipeline struct {
+	redactors []Redactor
+	policy    Policy
+}
+
+// NewRedactionPipeline builds a pipeline that runs redactors (in order,
+// accumulating all of their matches) and hands the result to policy.
+func NewRedactionPipeline(policy Policy, redactors ...Redactor) *RedactionPipeline {
+	return &RedactionPipeline{redactors: redactors, policy: policy}
+}
+
+// Scrub applies every redactor to value and returns the policy's decision
+// plus which kinds of content were found, deduplicated.
+func (p *RedactionPipeline) Scrub(ctx context.Context, value string) (result string, drop bool, kinds []RedactionKind) {
+	var matches []Match
+	for _, r := range p.redactors {
+		matches = append(matches, r.Detect(ctx, value)...)
+	}
+	if len(matches) == 0 {
+		return value, false, nil
+	}
+
+	seen := make(map[RedactionKind]bool)
+	for _, m := range matches {
+		if !seen[m.Kind] {
+			seen[m.Kind] = true
+			kinds = append(kinds, m.Kind)
+		}
+	}
+
+	result, drop = p.policy.Apply(value, matches)
+	return result, drop, kinds
+}
+
+// ScrubAttributes scrubs every string value in attrs in place, returning how
+// many fields were modified, whether any of them should cause the whole
+// event to be dropped, and the deduplicated set of kinds found.
+func (p *RedactionPipeline) ScrubAttributes(ctx context.Context, attrs map[string]interface{}) (count int, drop bool, kinds []RedactionKind) {
+	kindSet := make(map[RedactionKind]bool)
+	for key, v := range attrs {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+
+		scrubbed, shouldDrop, found := p.Scrub(ctx, s)
+		if len(found) == 0 {
+			continue
+		}
+
+		count++
+		attrs[key] = scrubbed
+		if shouldDrop {
+			drop = true
+		}
+		for _, kind := range found {
+			kindSet[kind] = true
+		}
+	}
+
+	for kind := range kindSet {
+		kinds = append(kinds, kind)
+	}
+	return count, drop, kinds
+}
+
+// ScrubAttributeMaps scrubs every map in attrsList in place the same way
+// ScrubAttributes does, aggregating counts/drop/kinds across all of them.
+// Useful for a span's list of events, each carrying its own attribute map.
+func (p *RedactionPipeline) ScrubAttributeMaps(ctx context.Context, attrsList []map[string]interface{}) (count int, drop bool, kinds []RedactionKind) {
+	kindSet := make(map[RedactionKind]bool)
+	for _, attrs := range attrsList {
+		c, d, found := p.ScrubAttributes(ctx, attrs)
+		count += c
+		if d {
+			drop = true
+		}
+		for _, kind := range found {
+			kindSet[kind] = true
+		}
+	}
+
+	for kind := range kindSet {
+		kinds = append(kinds, kind)
+	}
+	return count, drop, kinds
+}
+
+// replaceMatches rewrites value by passing each non-overlapping match (in
+// left-to-right order) through repl, leaving everything else untouched.
+// Overlapping or out-of-range matches are skipped rather than corrupting
+// the output.
+func replaceMatches(value string, matches []Match, repl func(Match) string) string {
+	sorted := append([]Match(nil), matches...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var b strings.Builder
+	last := 0
+	for _, m := range sorted {
+		if m.Start < last || m.Start < 0 || m.End > len(value) || m.Start > m.End {
+			continue
+		}
+		b.WriteString(value[last:m.Start])
+		b.WriteString(repl(m))
+		last = m.End
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+// MarkerPolicy replaces each match with "[REDACTED:<kind>]".
+type MarkerPolicy struct{}
+
+func (MarkerPolicy) Apply(value string, matches []Match) (string, bool) {
+	return replaceMatches(value, matches, func(m Match) string {
+		return fmt.Sprintf("[REDACTED:%s]", m.Kind)
+	}), false
+}
+
+// HashPolicy replaces each match with a "sha256:<hex>" digest of the
+// matched text, preserving the ability to correlate repeated occurrences
+// of the same secret without retaining it.
+type HashPolicy struct{}
+
+func (HashPolicy) Apply(value string, matches []Match) (string, bool) {
+	return replaceMatches(value, matches, func(m Match) string {
+		sum := sha256.Sum256([]byte(value[m.Start:m.End]))
+		return fmt.Sprintf("sha256:%x", sum)
+	}), false
+}
+
+// DropPolicy discards the whole event whenever any redactor finds a match,
+// rather than trying to sanitize it in place.
+type DropPolicy struct{}
+
+func (DropPolicy) Apply(value string, matches []Match) (string, bool) {
+	return value, true
+}
+
+// RegexRedactor reports every match of Pattern as Kind.
+type RegexRedactor struct {
+	Kind    RedactionKind
+	Pattern *regexp.Regexp
+}
+
+func (r *RegexRedactor) Detect(ctx context.Context, value string) []Match {
+	var matches []Match
+	for _, loc := range r.Pattern.FindAllStringIndex(value, -1) {
+		matches = append(matches, Match{Kind: r.Kind, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// NewEmailRedactor flags email addresses.
+func NewEmailRedactor() *RegexRedactor {
+	return &RegexRedactor{Kind: RedactionEmail, Pattern: emailPattern}
+}
+
+// apiKeyPattern covers common vendor API key prefixes (OpenAI, Stripe,
+// GitHub, AWS) plus bare "Bearer <token>" headers.
+var apiKeyPattern = regexp.MustCompile(`\b(?:sk|pk)-[A-Za-z0-9]{16,}\b|\bgh[pousr]_[A-Za-z0-9]{36}\b|\bAKIA[0-9A-Z]{16}\b|\bBearer\s+[A-Za-z0-9\-_.]{20,}\b`)
+
+// NewAPIKeyRedactor flags strings matching well-known API key shapes.
+func NewAPIKeyRedactor() *RegexRedactor {
+	return &RegexRedactor{Kind: RedactionAPIKey, Pattern: apiKeyPattern}
+}
+
+// creditCardPattern finds runs of 13-19 digits, optionally grouped with
+// spaces or hyphens; CreditCardRedactor then Luhn-checks each candidate to
+// cut down on false positives from other numeric IDs.
+var creditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){13,19}\b`)
+
+// CreditCardRedactor flags digit runs that pass the Luhn checksum used by
+// card numbers.
+type CreditCardRedactor struct{}
+
+func (CreditCardRedactor) Detect(ctx context.Context, value string) []Match {
+	var matches []Match
+	for _, loc := range creditCardPattern.FindAllStringIndex(value, -1) {
+		digits := stripNonDigits(value[loc[0]:loc[1]])
+		if len(digits) < 13 || len(digits) > 19 || !IsValidLuhn(digits) {
+			continue
+		}
+		matches = append(matches, Match{Kind: RedactionCreditCard, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+func stripNonDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// IsValidLuhn reports whether digits (a string of decimal digits) passes
+// the Luhn checksum used by credit card numbers.
+func IsValidLuhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// tokenPattern matches runs of token-like characters long enough to be
+// worth entropy-checking; EntropySecretRedactor filters these down further.
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_\-]{20,}`)
+
+// EntropySecretRedactor flags long tokens whose character distribution
+// looks random (high Shannon entropy per character), catching secrets that
+// don't match any known vendor prefix.
+type EntropySecretRedactor struct {
+	// MinLength is the shortest token considered. Defaults to 20.
+	MinLength int
+	// MinBitsPerChar is the entropy threshold, in bits per character, a
+	// token must meet to be flagged. Defaults to 3.5, which passes over
+	// most natural-language text and common identifiers while catching
+	// base64/hex-like secrets.
+	MinBitsPerChar float64
+}
+
+// NewEntropySecretRedactor returns an EntropySecretRedactor with reasonable
+// defaults.
+func NewEntropySecretRedactor() *EntropySecretRedactor {
+	return &EntropySecretRedactor{MinLength: 20, MinBitsPerChar: 3.5}
+}
+
+func (r *EntropySecretRedactor) Detect(ctx context.Context, value string) []Match {
+	minLen := r.MinLength
+	if minLen <= 0 {
+		minLen = 20
+	}
+	minBits := r.MinBitsPerChar
+	if minBits <= 0 {
+		minBits = 3.5
+	}
+
+	var matches []Match
+	for _, loc := range tokenPattern.FindAllStringIndex(value, -1) {
+		token := value[loc[0]:loc[1]]
+		if len(token) < minLen {
+			continue
+		}
+		if shannonEntropy(token) < minBits {
+			continue
+		}
+		matches = append(matches, Match{Kind: RedactionSecret, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+func shannonEntropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+
+	n := float64(len(s))
+	var entropy float64
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// DLPRedactor adapts an external DLP service into the Redactor interface.
+// Scan is expected to call out to whatever service the caller runs; a
+// failed or unset Scan is treated as "no matches" so a DLP outage doesn't
+// block event emission or exporting.
+type DLPRedactor struct {
+	Scan func(ctx context.Context, value string) ([]Match, error)
+}
+
+func (d *DLPRedactor) Detect(ctx context.Context, value string) []Match {
+	if d.Scan == nil {
+		return nil
+	}
+	matches, err := d.Scan(ctx, value)
+	if err != nil {
+		return nil
+	}
+	return matches
+}
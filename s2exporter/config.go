@@ -11,6 +11,10 @@ type Config struct {
 	StreamPrefix  string        `mapstructure:"stream_prefix"`
 	BatchSize     int           `mapstructure:"batch_size"`
 	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// RetryQueuePath is where batches that failed to flush are persisted so
+	// they survive a collector restart instead of being dropped.
+	RetryQueuePath string `mapstructure:"retry_queue_path"`
 }
 
 func (c *Config) Validate() error {
@@ -29,6 +33,9 @@ func (c *Config) Validate() error {
 	if c.StreamPrefix == "" {
 		c.StreamPrefix = "agent-session-"
 	}
+	if c.RetryQueuePath == "" {
+		c.RetryQueuePath = "s2exporter-retry.jsonl"
+	}
 	return nil
 }
 
@@ -1,7 +1,9 @@
 package s2exporter
 
 import (
+	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -38,12 +40,44 @@ type S2Event struct {
 	Duration       time.Duration          `json:"duration_ns"`
 	Status         string                 `json:"status"`
 	Attributes     map[string]interface{} `json:"attributes"`
+	Events         []EventRecord          `json:"events,omitempty"`
 }
 
-type EventConverter struct{}
+// EventRecord mirrors one span event carried through from the original
+// OTEL span (a gen_ai.user.message, gen_ai.assistant.message,
+// gen_ai.tool.message, or gen_ai.choice event), so S2 consumers see the
+// structured conversation and tool-call history instead of just the span's
+// final attributes.
+type EventRecord struct {
+	Name       string                 `json:"name"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type EventConverter struct {
+	redaction *RedactionPipeline
+}
+
+// EventConverterOption configures an EventConverter built by
+// NewEventConverter.
+type EventConverterOption func(*EventConverter)
 
-func NewEventConverter() *EventConverter {
-	return &EventConverter{}
+// WithRedaction installs a RedactionPipeline that scrubs every string
+// attribute (and span event attribute) convertSpan produces, mirroring
+// agentsdk.WithRedactor on the SDK side of the same gen_ai.redaction.*
+// convention.
+func WithRedaction(pipeline *RedactionPipeline) EventConverterOption {
+	return func(c *EventConverter) {
+		c.redaction = pipeline
+	}
+}
+
+func NewEventConverter(opts ...EventConverterOption) *EventConverter {
+	c := &EventConverter{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *EventConverter) ConvertTraces(td ptrace.Traces) []*S2Event {
@@ -62,7 +96,9 @@ func (c *EventConverter) ConvertTraces(td ptrace.Traces) []*S2Event {
 			for k := 0; k < spans.Len(); k++ {
 				span := spans.At(k)
 				event := c.convertSpan(span, resourceAttrs)
-				events = append(events, event)
+				if event != nil {
+					events = append(events, event)
+				}
 			}
 		}
 	}
@@ -99,6 +135,27 @@ func (c *EventConverter) convertSpan(span ptrace.Span, resourceAttrs map[string]
 		parentSpanID = span.ParentSpanID().String()
 	}
 
+	filtered := filterGenAIAttributes(attrs)
+	spanEvents := extractSpanEvents(span)
+
+	if c.redaction != nil {
+		// ConvertTraces runs in a collector processor path with no request
+		// context to thread through; context.Background() is the same
+		// no-op ctx the shared pipeline's built-in Redactors ignore.
+		ctx := context.Background()
+		attrCount, attrDrop, attrKinds := c.redaction.ScrubAttributes(ctx, filtered)
+		eventCount, eventDrop, eventKinds := c.redaction.ScrubAttributeMaps(ctx, eventAttributeMaps(spanEvents))
+
+		if attrDrop || eventDrop {
+			return nil
+		}
+		if count := attrCount + eventCount; count > 0 {
+			filtered["gen_ai.redaction.applied"] = true
+			filtered["gen_ai.redaction.count"] = count
+			filtered["gen_ai.redaction.kinds"] = mergeRedactionKinds(attrKinds, eventKinds)
+		}
+	}
+
 	event := &S2Event{
 		Timestamp:      span.StartTimestamp().AsTime(),
 		TraceID:        span.TraceID().String(),
@@ -109,12 +166,59 @@ func (c *EventConverter) convertSpan(span ptrace.Span, resourceAttrs map[string]
 		SpanName:       span.Name(),
 		Duration:       time.Duration(span.EndTimestamp() - span.StartTimestamp()),
 		Status:         status,
-		Attributes:     filterGenAIAttributes(attrs),
+		Attributes:     filtered,
+		Events:         spanEvents,
 	}
 
 	return event
 }
 
+// mergeRedactionKinds deduplicates the kinds found across an event's
+// attributes and its span events into a single string slice.
+func mergeRedactionKinds(a, b []RedactionKind) []string {
+	seen := make(map[RedactionKind]bool, len(a)+len(b))
+	var kinds []string
+	for _, k := range append(append([]RedactionKind(nil), a...), b...) {
+		if !seen[k] {
+			seen[k] = true
+			kinds = append(kinds, string(k))
+		}
+	}
+	return kinds
+}
+
+// extractSpanEvents carries a span's events through to the S2Event instead
+// of discarding them, so gen_ai.*.message and gen_ai.choice events survive
+// the conversion.
+func extractSpanEvents(span ptrace.Span) []EventRecord {
+	spanEvents := span.Events()
+	if spanEvents.Len() == 0 {
+		return nil
+	}
+
+	records := make([]EventRecord, spanEvents.Len())
+	for i := 0; i < spanEvents.Len(); i++ {
+		se := spanEvents.At(i)
+		records[i] = EventRecord{
+			Name:       se.Name(),
+			Timestamp:  se.Timestamp().AsTime(),
+			Attributes: extractAttributes(se.Attributes()),
+		}
+	}
+	return records
+}
+
+// eventAttributeMaps collects each record's Attributes map by reference, so
+// scrubbing them through RedactionPipeline.ScrubAttributeMaps mutates the
+// records in place.
+func eventAttributeMaps(records []EventRecord) []map[string]interface{} {
+	maps := make([]map[string]interface{}, len(records))
+	for i := range records {
+		maps[i] = records[i].Attributes
+	}
+	return maps
+}
+
 func extractAttributes(attrs pcommon.Map) map[string]interface{} {
 	result := make(map[string]interface{})
 	attrs.Range(func(k string, v pcommon.Value) bool {
@@ -157,22 +261,18 @@ func getStringAttr(attrs map[string]interface{}, key string) string {
 	return ""
 }
 
+// genAIAttrPrefix is the namespace every GenAI semantic-convention attribute
+// falls under. Matching by prefix means new gen_ai.* attributes show up in
+// exported events automatically, without a hand-maintained allowlist.
+const genAIAttrPrefix = "gen_ai."
+
 func filterGenAIAttributes(attrs map[string]interface{}) map[string]interface{} {
 	filtered := make(map[string]interface{})
-	genAIKeys := []string{
-		AttrConversationID, AttrAgentID, AttrAgentName, AttrOperationName,
-		AttrToolName, AttrToolCallID, AttrToolCallArgs, AttrToolCallResult,
-		AttrInputMessages, AttrOutputMessages, AttrSystemPrompt,
-		AttrProviderName, AttrRequestModel, AttrResponseModel,
-		AttrInputTokens, AttrOutputTokens,
-	}
-
-	for _, key := range genAIKeys {
-		if v, ok := attrs[key]; ok {
+	for key, v := range attrs {
+		if strings.HasPrefix(key, genAIAttrPrefix) {
 			filtered[key] = v
 		}
 	}
-
 	return filtered
 }
 
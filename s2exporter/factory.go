@@ -24,10 +24,11 @@ func NewFactory() exporter.Factory {
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		Endpoint:      "https://api.s2.dev",
-		StreamPrefix:  "agent-session-",
-		BatchSize:     100,
-		FlushInterval: 5 * time.Second,
+		Endpoint:       "https://api.s2.dev",
+		StreamPrefix:   "agent-session-",
+		BatchSize:      100,
+		FlushInterval:  5 * time.Second,
+		RetryQueuePath: "s2exporter-retry.jsonl",
 	}
 }
 
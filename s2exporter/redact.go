@@ -0,0 +1,81 @@
+package s2exporter
+
+import "github.com/agent-observability/internal/redact"
+
+// The types below are aliases onto the shared redaction pipeline in
+// internal/redact, which also backs agentsdk.RedactionPipeline. Keeping them
+// here (rather than asking callers to import internal/redact directly,
+// which they couldn't anyway) preserves the s2exporter.RedactionPipeline /
+// s2exporter.WithRedaction API this package has always exposed.
+
+// RedactionKind identifies what category of sensitive content a Redactor
+// found, and is recorded in the gen_ai.redaction.kinds attribute.
+type RedactionKind = redact.RedactionKind
+
+const (
+	RedactionEmail      = redact.RedactionEmail
+	RedactionCreditCard = redact.RedactionCreditCard
+	RedactionAPIKey     = redact.RedactionAPIKey
+	RedactionSecret     = redact.RedactionSecret
+	RedactionDLP        = redact.RedactionDLP
+)
+
+// Match is one span of sensitive content a Redactor found within a string.
+type Match = redact.Match
+
+// Redactor finds sensitive content within a string attribute value.
+type Redactor = redact.Redactor
+
+// Policy decides what to do with the matches a Redactor found.
+type Policy = redact.Policy
+
+// RedactionPipeline runs a set of Redactors over S2Event attributes and
+// span events and lets a single Policy decide what happens to whatever
+// they find. Install one on an EventConverter via WithRedaction, mirroring
+// agentsdk.RedactionPipeline on the SDK side of the same gen_ai.redaction.*
+// convention.
+type RedactionPipeline = redact.RedactionPipeline
+
+// NewRedactionPipeline builds a pipeline that runs redactors (in order,
+// accumulating all of their matches) and hands the result to policy.
+var NewRedactionPipeline = redact.NewRedactionPipeline
+
+// MarkerPolicy replaces each match with "[REDACTED:<kind>]".
+type MarkerPolicy = redact.MarkerPolicy
+
+// HashPolicy replaces each match with a "sha256:<hex>" digest of the
+// matched text.
+type HashPolicy = redact.HashPolicy
+
+// DropPolicy discards the whole event whenever any redactor finds a match.
+type DropPolicy = redact.DropPolicy
+
+// RegexRedactor reports every match of Pattern as Kind.
+type RegexRedactor = redact.RegexRedactor
+
+// NewEmailRedactor flags email addresses.
+var NewEmailRedactor = redact.NewEmailRedactor
+
+// NewAPIKeyRedactor flags strings matching well-known API key shapes.
+var NewAPIKeyRedactor = redact.NewAPIKeyRedactor
+
+// CreditCardRedactor flags digit runs that pass the Luhn checksum used by
+// card numbers.
+type CreditCardRedactor = redact.CreditCardRedactor
+
+// EntropySecretRedactor flags long tokens whose character distribution
+// looks random, catching secrets that don't match any known vendor prefix.
+type EntropySecretRedactor = redact.EntropySecretRedactor
+
+// NewEntropySecretRedactor returns an EntropySecretRedactor with reasonable
+// defaults.
+var NewEntropySecretRedactor = redact.NewEntropySecretRedactor
+
+// DLPRedactor adapts an external DLP service into the Redactor interface.
+type DLPRedactor = redact.DLPRedactor
+
+// isValidLuhn reports whether digits (a string of decimal digits) passes
+// the Luhn checksum used by credit card numbers.
+func isValidLuhn(digits string) bool {
+	return redact.IsValidLuhn(digits)
+}
@@ -0,0 +1,111 @@
+package s2exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// retryBatch is one failed flushBatch call, persisted so it survives a
+// collector restart instead of being dropped.
+type retryBatch struct {
+	StreamID string     `json:"stream_id"`
+	Events   []*S2Event `json:"events"`
+}
+
+// retryQueue is a small file-backed backlog of batches that failed to
+// flush to S2. flushBatch appends to it instead of discarding the batch,
+// and the exporter's flush loop periodically tries to drain it.
+type retryQueue struct {
+	path string
+	mu   sync.Mutex
+}
+
+func newRetryQueue(path string) *retryQueue {
+	return &retryQueue{path: path}
+}
+
+// Enqueue appends a failed batch to the backlog file.
+func (q *retryQueue) Enqueue(streamID string, events []*S2Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	f, err := os.OpenFile(q.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open retry queue: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(retryBatch{StreamID: streamID, Events: events})
+	if err != nil {
+		return fmt.Errorf("failed to marshal retry batch: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to append retry batch: %w", err)
+	}
+	return nil
+}
+
+// Drain retries every backlogged batch via flush. Batches that still fail
+// are written back to the queue for the next call; everything else is
+// dropped from it.
+func (q *retryQueue) Drain(logger *zap.Logger, flush func(streamID string, events []*S2Event) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	f, err := os.Open(q.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("Failed to open retry queue for draining", zap.Error(err))
+		}
+		return
+	}
+
+	var remaining []retryBatch
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var batch retryBatch
+		if err := json.Unmarshal(scanner.Bytes(), &batch); err != nil {
+			logger.Warn("Dropping unreadable retry batch", zap.Error(err))
+			continue
+		}
+		if err := flush(batch.StreamID, batch.Events); err != nil {
+			logger.Warn("Retry batch still failing", zap.Error(err), zap.String("stream", batch.StreamID))
+			remaining = append(remaining, batch)
+		}
+	}
+	f.Close()
+
+	if err := scanner.Err(); err != nil {
+		logger.Error("Failed to read retry queue", zap.Error(err))
+	}
+
+	q.rewrite(remaining)
+}
+
+func (q *retryQueue) rewrite(batches []retryBatch) {
+	if len(batches) == 0 {
+		_ = os.Remove(q.path)
+		return
+	}
+
+	tmp := q.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return
+	}
+	for _, batch := range batches {
+		line, err := json.Marshal(batch)
+		if err != nil {
+			continue
+		}
+		f.Write(append(line, '\n'))
+	}
+	f.Close()
+	os.Rename(tmp, q.path)
+}
@@ -11,10 +11,11 @@ import (
 )
 
 type s2Exporter struct {
-	config    *Config
-	logger    *zap.Logger
-	client    *S2Client
-	converter *EventConverter
+	config     *Config
+	logger     *zap.Logger
+	client     *S2Client
+	converter  *EventConverter
+	retryQueue *retryQueue
 
 	bufferMu sync.Mutex
 	buffers  map[string][]*S2Event
@@ -32,12 +33,13 @@ func newS2Exporter(cfg *Config, logger *zap.Logger) (*s2Exporter, error) {
 	converter := NewEventConverter()
 
 	return &s2Exporter{
-		config:    cfg,
-		logger:    logger,
-		client:    client,
-		converter: converter,
-		buffers:   make(map[string][]*S2Event),
-		stopCh:    make(chan struct{}),
+		config:     cfg,
+		logger:     logger,
+		client:     client,
+		converter:  converter,
+		retryQueue: newRetryQueue(cfg.RetryQueuePath),
+		buffers:    make(map[string][]*S2Event),
+		stopCh:     make(chan struct{}),
 	}, nil
 }
 
@@ -71,7 +73,10 @@ func (e *s2Exporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 			e.buffers[streamID] = nil
 			e.bufferMu.Unlock()
 			if err := e.flushBatch(ctx, streamID, batch); err != nil {
-				e.logger.Error("Failed to flush batch", zap.Error(err), zap.String("stream", streamID))
+				e.logger.Error("Failed to flush batch, queued for retry", zap.Error(err), zap.String("stream", streamID))
+				if qerr := e.retryQueue.Enqueue(streamID, batch); qerr != nil {
+					e.logger.Error("Failed to queue batch for retry", zap.Error(qerr), zap.String("stream", streamID))
+				}
 			}
 			e.bufferMu.Lock()
 		}
@@ -90,6 +95,9 @@ func (e *s2Exporter) flushLoop() {
 		select {
 		case <-ticker.C:
 			e.flushAllBuffers(context.Background())
+			e.retryQueue.Drain(e.logger, func(streamID string, events []*S2Event) error {
+				return e.flushBatch(context.Background(), streamID, events)
+			})
 		case <-e.stopCh:
 			return
 		}
@@ -109,10 +117,13 @@ func (e *s2Exporter) flushAllBuffers(ctx context.Context) {
 
 	for streamID, events := range buffersToFlush {
 		if err := e.flushBatch(ctx, streamID, events); err != nil {
-			e.logger.Error("Failed to flush buffer",
+			e.logger.Error("Failed to flush buffer, queued for retry",
 				zap.Error(err),
 				zap.String("stream", streamID),
 				zap.Int("event_count", len(events)))
+			if qerr := e.retryQueue.Enqueue(streamID, events); qerr != nil {
+				e.logger.Error("Failed to queue buffer for retry", zap.Error(qerr), zap.String("stream", streamID))
+			}
 		}
 	}
 }
@@ -277,4 +277,66 @@ func TestGetStreamID(t *testing.T) {
 	}
 }
 
+func TestEventConverterRedactsAttributes(t *testing.T) {
+	pipeline := NewRedactionPipeline(MarkerPolicy{}, NewEmailRedactor())
+	converter := NewEventConverter(WithRedaction(pipeline))
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	ss := rs.ScopeSpans().AppendEmpty()
+	span := ss.Spans().AppendEmpty()
+	span.SetName("test-span")
+	span.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.Attributes().PutStr(AttrOperationName, "invoke_agent")
+	span.Attributes().PutStr(AttrAgentName, "reach me at jane@example.com please")
+
+	events := converter.ConvertTraces(td)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	got := events[0].Attributes[AttrAgentName]
+	want := "reach me at [REDACTED:email] please"
+	if got != want {
+		t.Errorf("Attributes[%s] = %q, want %q", AttrAgentName, got, want)
+	}
+	if applied, _ := events[0].Attributes["gen_ai.redaction.applied"].(bool); !applied {
+		t.Errorf("gen_ai.redaction.applied = %v, want true", events[0].Attributes["gen_ai.redaction.applied"])
+	}
+}
+
+func TestEventConverterDropPolicyDiscardsEvent(t *testing.T) {
+	pipeline := NewRedactionPipeline(DropPolicy{}, NewEmailRedactor())
+	converter := NewEventConverter(WithRedaction(pipeline))
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	ss := rs.ScopeSpans().AppendEmpty()
+	span := ss.Spans().AppendEmpty()
+	span.SetName("test-span")
+	span.Attributes().PutStr(AttrAgentName, "jane@example.com")
+
+	events := converter.ConvertTraces(td)
+	if len(events) != 0 {
+		t.Fatalf("expected event to be dropped, got %d", len(events))
+	}
+}
+
+func TestIsValidLuhn(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{"4111111111111111", true},  // well-known test Visa number
+		{"4111111111111112", false}, // fails checksum
+	}
+
+	for _, tt := range tests {
+		if got := isValidLuhn(tt.digits); got != tt.want {
+			t.Errorf("isValidLuhn(%q) = %v, want %v", tt.digits, got, tt.want)
+		}
+	}
+}
+
 